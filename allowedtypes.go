@@ -0,0 +1,169 @@
+package myerrorlint
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// formatFuncs are fmt functions formatting args: index of format (-1 if none) and of variadic args
+var formatFuncs = map[string][2]int{
+	"Errorf":   {0, 1},
+	"Sprintf":  {0, 1},
+	"Printf":   {0, 1},
+	"Fprintf":  {1, 2},
+	"Sprint":   {-1, 0},
+	"Sprintln": {-1, 0},
+	"Print":    {-1, 0},
+	"Println":  {-1, 0},
+	"Fprint":   {-1, 1},
+	"Fprintln": {-1, 1},
+}
+
+// checkAllowedTypes checks allowed types declared in package: they implement error with receiver kind listed,
+// their Unwrap, Is and As methods have signatures used by errors package, their Error does not format receiver
+func checkAllowedTypes(pass *analysis.Pass, cfg *Config, res *Result, prog *ssa.Program) {
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		t := obj.Type()
+		ptr := types.NewPointer(t)
+		methodsChecked := false
+		for _, allowed := range []types.Type{t, ptr} {
+			if !isAllowedErrorType(allowed, cfg) {
+				continue
+			}
+			report := func(pos token.Pos, format string, args ...interface{}) {
+				reportFinding(pass, cfg, res, Finding{Pos: pos, Rule: RuleAllowedType, Func: allowed.String(), Origin: allowed.String(),
+					Message: fmt.Sprintf(format, args...)})
+			}
+			if !isErrorType(allowed) {
+				if allowed == t && isErrorType(ptr) {
+					report(obj.Pos(), "allowed type %s does not implement error: Error has pointer receiver, allow %s", t, ptr)
+				} else {
+					report(obj.Pos(), "allowed type %s does not implement error", allowed)
+				}
+				continue
+			}
+			if methodsChecked {
+				// both type and pointer are allowed
+				continue
+			}
+			methodsChecked = true
+			checkErrorMethods(ptr, report)
+			if method := lookupMethod(allowed, "Error"); method != nil {
+				if fn := prog.FuncValue(method); fn != nil && fn.Pkg != nil && fn.Pkg.Pkg == pass.Pkg {
+					checkErrorRecursion(fn, report)
+				}
+			}
+		}
+	}
+}
+
+func lookupMethod(t types.Type, name string) *types.Func {
+	sel := types.NewMethodSet(t).Lookup(nil, name)
+	if sel == nil {
+		return nil
+	}
+	method, _ := sel.Obj().(*types.Func)
+	return method
+}
+
+// checkErrorMethods checks signatures of methods used by errors.Unwrap, errors.Is and errors.As
+func checkErrorMethods(t types.Type, report func(pos token.Pos, format string, args ...interface{})) {
+	errorSlice := types.NewSlice(errorType)
+	boolType := types.Typ[types.Bool]
+	emptyInterface := types.NewInterfaceType(nil, nil)
+	checks := []struct {
+		name    string
+		want    string
+		matches func(sig *types.Signature) bool
+	}{
+		{"Unwrap", "Unwrap() error or Unwrap() []error", func(sig *types.Signature) bool {
+			return sig.Params().Len() == 0 && sig.Results().Len() == 1 &&
+				(types.Identical(sig.Results().At(0).Type(), errorType) || types.Identical(sig.Results().At(0).Type(), errorSlice))
+		}},
+		{"Is", "Is(error) bool", func(sig *types.Signature) bool {
+			return sig.Params().Len() == 1 && types.Identical(sig.Params().At(0).Type(), errorType) &&
+				sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), boolType)
+		}},
+		{"As", "As(interface{}) bool", func(sig *types.Signature) bool {
+			return sig.Params().Len() == 1 && types.Identical(sig.Params().At(0).Type(), emptyInterface) &&
+				sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), boolType)
+		}},
+	}
+	for _, check := range checks {
+		method := lookupMethod(t, check.name)
+		if method == nil {
+			continue
+		}
+		if !check.matches(method.Type().(*types.Signature)) {
+			report(method.Pos(), "method %s of allowed type %s is not used by errors package: want %s", check.name, t, check.want)
+		}
+	}
+}
+
+// callsError checks if fmt calls Error method of error formatted with verb:
+// %#v uses GoString or prints fields
+func callsError(v formatVerb) bool {
+	switch v.verb {
+	case 'v':
+		return !v.sharp
+	case 's', 'q', 'x', 'X':
+		return true
+	}
+	return false
+}
+
+// checkErrorRecursion reports formatting of receiver implementing error by fmt in Error method: it calls Error again
+func checkErrorRecursion(fn *ssa.Function, report func(pos token.Pos, format string, args ...interface{})) {
+	if len(fn.Params) == 0 {
+		return
+	}
+	recv := fn.Params[0]
+	isRecv := func(v ssa.Value) bool {
+		if v == recv {
+			return true
+		}
+		load, ok := v.(*ssa.UnOp)
+		return ok && load.Op == token.MUL && load.X == recv
+	}
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			function := call.Common().StaticCallee()
+			if function == nil || funcPkgPath(function) != "fmt" {
+				continue
+			}
+			indices, ok := formatFuncs[function.Name()]
+			args := call.Common().Args
+			if !ok || indices[1] >= len(args) {
+				continue
+			}
+			var verbs []formatVerb
+			known := false
+			if indices[0] >= 0 {
+				verbs, known = formatVerbs(args[indices[0]])
+			}
+			for i, arg := range formatArgs(args[indices[1]]) {
+				conv, ok := arg.(*ssa.MakeInterface)
+				if !ok || !isRecv(conv.X) || !isErrorType(conv.X.Type()) {
+					continue
+				}
+				if known && (i >= len(verbs) || !callsError(verbs[i])) {
+					continue
+				}
+				report(call.Pos(), "Error method of %s formats its receiver by fmt.%s: infinite recursion", recv.Type(), function.Name())
+			}
+		}
+	}
+}
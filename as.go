@@ -0,0 +1,70 @@
+package myerrorlint
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// asFuncs are functions setting target to error of chain like errors.As
+var asFuncs = map[string]bool{
+	"errors.As":                        true,
+	"golang.org/x/xerrors.As":          true,
+	"github.com/pkg/errors.As":         true,
+	"github.com/cockroachdb/errors.As": true,
+}
+
+// asTarget returns variable value is loaded from and calls of As functions with pointer to it as target
+func asTarget(v ssa.Value) (alloc *ssa.Alloc, calls []*ssa.Call) {
+	load, ok := v.(*ssa.UnOp)
+	if !ok || load.Op != token.MUL {
+		return nil, nil
+	}
+	alloc, ok = load.X.(*ssa.Alloc)
+	if !ok {
+		return nil, nil
+	}
+	for _, instr := range *alloc.Referrers() {
+		conv, ok := instr.(*ssa.MakeInterface)
+		if !ok {
+			continue
+		}
+		for _, ref := range *conv.Referrers() {
+			call, ok := ref.(*ssa.Call)
+			if !ok {
+				continue
+			}
+			function := call.Call.StaticCallee()
+			if function == nil || !asFuncs[function.String()] || len(call.Call.Args) != 2 || call.Call.Args[1] != conv {
+				continue
+			}
+			calls = append(calls, call)
+		}
+	}
+	return alloc, calls
+}
+
+// checkAsTarget checks value loaded from target of errors.As.
+// Target of concrete type gets value of its type: it is checked as any value of the type.
+// Target of interface type gets error of chain of As argument: the argument is checked
+func (w *walker) checkAsTarget(v ssa.Value, defaultPos token.Pos, p path) bool {
+	if !types.IsInterface(v.Type()) {
+		return false
+	}
+	alloc, calls := asTarget(v)
+	if len(calls) == 0 {
+		return false
+	}
+	for _, instr := range *alloc.Referrers() {
+		if store, ok := instr.(*ssa.Store); ok {
+			pos := retPos(store, defaultPos)
+			w.follow(v, "store", store.Val, pos, p.with(pos, "assigned"))
+		}
+	}
+	for _, call := range calls {
+		pos := retPos(call, defaultPos)
+		w.follow(v, "errors-as", call.Call.Args[0], pos, p.with(pos, "matched by "+call.Call.StaticCallee().String()))
+	}
+	return true
+}
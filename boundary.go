@@ -0,0 +1,122 @@
+package myerrorlint
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// isBoundary checks if function is part of exported API with BoundaryOnly:
+// exported function, method of exported type or exported method of unexported type
+// (it implements interface that can be returned to clients).
+// Anonymous functions are checked as part of functions they are returned by or called in
+func isBoundary(fn *ssa.Function) bool {
+	if fn.Parent() != nil {
+		return false
+	}
+	if isExportedFunc(fn) {
+		return true
+	}
+	return fn.Signature.Recv() != nil && ast.IsExported(fn.Name())
+}
+
+// reportBoundaries reports findings of functions not on boundary which errors reach exported functions.
+// Finding is reported once for every exported function with chain of calls it comes through.
+// Functions called dynamically (by interface, as function or method value) may reach exported API
+// by calls that are not tracked: if they are not reached by static calls their findings are reported too
+func reportBoundaries(pass *analysis.Pass, cfg *Config, res *Result, funcs []*ssa.Function) {
+	reached := make(map[*ssa.Function]bool)
+	walk := func(root *ssa.Function, seen map[*ssa.Function]bool, suffix func(via string) string) {
+		var visit func(caller *ssa.Function, via []string)
+		visit = func(caller *ssa.Function, via []string) {
+			for _, ls := range res.sources[caller] {
+				callee := ls.callee
+				if callee == nil || seen[callee] || isBoundary(callee) {
+					continue
+				}
+				if _, ok := res.sources[callee]; !ok {
+					// not function of package
+					continue
+				}
+				seen[callee] = true
+				reached[callee] = true
+				calleeVia := append(append([]string(nil), via...), callee.RelString(nil))
+				for _, f := range res.summaries[callee] {
+					f.Message += suffix(strings.Join(calleeVia, " -> "))
+					reportFinding(pass, cfg, res, f)
+				}
+				visit(callee, calleeVia)
+			}
+		}
+		visit(root, nil)
+	}
+	for _, fn := range funcs {
+		if !isBoundary(fn) {
+			continue
+		}
+		name := fn.RelString(nil)
+		walk(fn, make(map[*ssa.Function]bool), func(via string) string {
+			return fmt.Sprintf(" (reaches exported %s via %s)", name, via)
+		})
+	}
+	dynamic := dynamicFuncs(funcs)
+	for _, fn := range funcs {
+		if isBoundary(fn) || reached[fn] || !dynamic[fn] {
+			continue
+		}
+		reached[fn] = true
+		name := fn.RelString(nil)
+		for _, f := range res.summaries[fn] {
+			f.Message += fmt.Sprintf(" (may reach exported API: %s is called dynamically)", name)
+			reportFinding(pass, cfg, res, f)
+		}
+		walk(fn, reached, func(via string) string {
+			return fmt.Sprintf(" (may reach exported API through dynamically called %s via %s)", name, via)
+		})
+	}
+}
+
+// dynamicFuncs returns functions that can be called not only by static calls:
+// methods (by interface) and functions and closures used as values
+func dynamicFuncs(funcs []*ssa.Function) map[*ssa.Function]bool {
+	dynamic := make(map[*ssa.Function]bool)
+	for _, fn := range funcs {
+		if fn.Signature.Recv() != nil {
+			dynamic[fn] = true
+		}
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				if closure, ok := instr.(*ssa.MakeClosure); ok {
+					if !onlyCalled(closure) {
+						dynamic[closure.Fn.(*ssa.Function)] = true
+					}
+					continue
+				}
+				var callee ssa.Value
+				if call, ok := instr.(ssa.CallInstruction); ok && !call.Common().IsInvoke() {
+					callee = call.Common().Value
+				}
+				for _, op := range instr.Operands(nil) {
+					if f, ok := (*op).(*ssa.Function); ok && *op != callee {
+						dynamic[f] = true
+					}
+				}
+			}
+		}
+	}
+	return dynamic
+}
+
+// onlyCalled checks if closure is only called in place like func() { ... }()
+func onlyCalled(closure *ssa.MakeClosure) bool {
+	for _, instr := range *closure.Referrers() {
+		call, ok := instr.(ssa.CallInstruction)
+		if !ok || call.Common().IsInvoke() || call.Common().Value != closure {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,235 @@
+package myerrorlint
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// formatVerb is verb of fmt format with its # flag
+type formatVerb struct {
+	verb  byte
+	sharp bool // alternate format like %#v
+}
+
+// formatVerbs returns verbs of constant fmt format by index of arg.
+// False if format is not constant or uses explicit arg indexes or * width
+func formatVerbs(v ssa.Value) ([]formatVerb, bool) {
+	c, ok := v.(*ssa.Const)
+	if !ok || c.Value == nil || c.Value.Kind() != constant.String {
+		return nil, false
+	}
+	format := constant.StringVal(c.Value)
+	var verbs []formatVerb
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		sharp := false
+		// flags, width and precision
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			sharp = sharp || format[i] == '#'
+			i++
+		}
+		if i >= len(format) {
+			break
+		}
+		switch format[i] {
+		case '%':
+			continue
+		case '[', '*':
+			return nil, false
+		}
+		verbs = append(verbs, formatVerb{verb: format[i], sharp: sharp})
+	}
+	return verbs, true
+}
+
+// formatArgs returns args passed to ...interface{} by index, nil for args not found
+func formatArgs(v ssa.Value) []ssa.Value {
+	slice, ok := v.(*ssa.Slice)
+	if !ok {
+		return nil
+	}
+	var args []ssa.Value
+	for _, instr := range *slice.X.Referrers() {
+		idxAddr, ok := instr.(*ssa.IndexAddr)
+		if !ok {
+			continue
+		}
+		index, ok := idxAddr.Index.(*ssa.Const)
+		if !ok {
+			continue
+		}
+		i := int(index.Int64())
+		for _, ref := range *idxAddr.Referrers() {
+			if store, ok := ref.(*ssa.Store); ok {
+				for len(args) <= i {
+					args = append(args, nil)
+				}
+				args[i] = store.Val
+			}
+		}
+	}
+	return args
+}
+
+// errorOfArg returns error passed as interface{} arg or nil
+func errorOfArg(v ssa.Value) ssa.Value {
+	switch conv := v.(type) {
+	case *ssa.MakeInterface:
+		if isErrorType(conv.X.Type()) {
+			return conv.X
+		}
+	case *ssa.ChangeInterface:
+		// error interface passed as interface{}
+		if isErrorType(conv.X.Type()) {
+			return conv.X
+		}
+	}
+	return nil
+}
+
+func isErrorf(function *ssa.Function) bool {
+	return function != nil && function.Name() == "Errorf" && funcPkgPath(function) == "fmt"
+}
+
+// checkCauses reports fmt.Errorf with error passed to %v or %s
+// and functions that drop cause: put only err.Error() of error parameter into returned error
+// or store it to error type without Unwrap
+func checkCauses(pass *analysis.Pass, cfg *Config, res *Result, fn *ssa.Function) {
+	report := func(pos token.Pos, origin, format string, args ...interface{}) {
+		reportFinding(pass, cfg, res, Finding{Pos: pos, Rule: RuleCauseDropped, Func: fn.RelString(nil), Origin: origin, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok || !isErrorf(call.Common().StaticCallee()) || len(call.Common().Args) != 2 {
+				continue
+			}
+			verbs, ok := formatVerbs(call.Common().Args[0])
+			if !ok {
+				continue
+			}
+			for i, arg := range formatArgs(call.Common().Args[1]) {
+				if arg == nil || errorOfArg(arg) == nil || i >= len(verbs) {
+					continue
+				}
+				if verbs[i].verb == 'v' || verbs[i].verb == 's' {
+					report(call.Pos(), "fmt.Errorf", "cause dropped: error passed to fmt.Errorf with %%%c, use %%w", verbs[i].verb)
+				}
+			}
+		}
+	}
+
+	if len(errorsBySignature(fn.Signature)) == 0 {
+		return
+	}
+	var returned map[ssa.Value]bool
+	for _, param := range fn.Params {
+		if !isErrorInterface(param.Type()) || param.Referrers() == nil {
+			continue
+		}
+		kept := false
+		var messageCall *ssa.Call
+		var field *types.Var
+		var fieldOf types.Type
+		for _, instr := range *param.Referrers() {
+			switch instr := instr.(type) {
+			case *ssa.Call:
+				if common := instr.Common(); common.IsInvoke() && common.Method.Name() == "Error" && common.Value == param {
+					if returned == nil {
+						returned = returnedErrorValues(fn)
+					}
+					if messageCall == nil && returned[instr] {
+						messageCall = instr
+					}
+					continue
+				}
+				kept = true
+			case *ssa.BinOp:
+				// err != nil
+			case *ssa.Store:
+				fieldAddr, ok := instr.Addr.(*ssa.FieldAddr)
+				if !ok || instr.Val != param {
+					kept = true
+					continue
+				}
+				fieldOf = fieldAddr.X.Type().Underlying().(*types.Pointer).Elem()
+				if !isErrorType(fieldOf) && !isErrorType(types.NewPointer(fieldOf)) || hasUnwrap(fieldOf) {
+					// not error type may keep cause for other use
+					kept = true
+					continue
+				}
+				field = fieldOf.Underlying().(*types.Struct).Field(fieldAddr.Field)
+			default:
+				kept = true
+			}
+		}
+		switch {
+		case kept:
+		case field != nil:
+			report(param.Pos(), param.Name(), "cause dropped: error parameter %s is stored to field %s of %s without Unwrap method", param.Name(), field.Name(), fieldOf)
+		case messageCall != nil:
+			report(messageCall.Pos(), param.Name(), "cause dropped: only message of error parameter %s is used", param.Name())
+		}
+	}
+}
+
+// returnedErrorValues returns values returned errors of function are made of:
+// operands of returned errors and values stored to memory they are loaded from
+func returnedErrorValues(fn *ssa.Function) map[ssa.Value]bool {
+	values := make(map[ssa.Value]bool)
+	var visit func(v ssa.Value)
+	visit = func(v ssa.Value) {
+		if v == nil || values[v] {
+			return
+		}
+		values[v] = true
+		if instr, ok := v.(ssa.Instruction); ok {
+			for _, op := range instr.Operands(nil) {
+				visit(*op)
+			}
+		}
+		if v.Referrers() == nil {
+			return
+		}
+		switch v.(type) {
+		case *ssa.Alloc, *ssa.FieldAddr, *ssa.IndexAddr:
+			for _, ref := range *v.Referrers() {
+				switch ref := ref.(type) {
+				case *ssa.Store:
+					if ref.Addr == v {
+						visit(ref.Val)
+					}
+				case *ssa.FieldAddr:
+					visit(ref)
+				case *ssa.IndexAddr:
+					visit(ref)
+				}
+			}
+		}
+	}
+	errorsAtReturn := errorsBySignature(fn.Signature)
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			if ret, ok := instr.(*ssa.Return); ok {
+				for _, i := range errorsAtReturn {
+					visit(ret.Results[i])
+				}
+			}
+		}
+	}
+	return values
+}
+
+// hasUnwrap checks if type or pointer to it has Unwrap method
+func hasUnwrap(t types.Type) bool {
+	return types.NewMethodSet(types.NewPointer(t)).Lookup(nil, "Unwrap") != nil
+}
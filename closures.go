@@ -0,0 +1,136 @@
+package myerrorlint
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// funcsBySignature returns indices of return variables of function types returning error
+func funcsBySignature(sign *types.Signature) []int {
+	results := sign.Results()
+	var res []int
+	for i := 0; i < results.Len(); i++ {
+		if fnSign, ok := results.At(i).Type().Underlying().(*types.Signature); ok && len(errorsBySignature(fnSign)) > 0 {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// returnedFuncs returns function values returned value can be: closures, functions, bound methods, ...
+func returnedFuncs(v ssa.Value, seen map[ssa.Value]bool) []ssa.Value {
+	if seen[v] {
+		return nil
+	}
+	seen[v] = true
+	switch v := v.(type) {
+	case *ssa.Phi:
+		var res []ssa.Value
+		for _, edge := range v.Edges {
+			res = append(res, returnedFuncs(edge, seen)...)
+		}
+		return res
+	case *ssa.ChangeType:
+		return returnedFuncs(v.X, seen)
+	case *ssa.Const:
+		// nil func
+		return nil
+	}
+	return []ssa.Value{v}
+}
+
+// boundMethod returns method of method value (closure of "$bound" wrapper) or nil
+func boundMethod(fn *ssa.Function) *types.Func {
+	if fn.Synthetic == "" || !strings.HasSuffix(fn.Name(), "$bound") {
+		return nil
+	}
+	method, _ := fn.Object().(*types.Func)
+	return method
+}
+
+// collectFactories finds closures returned by functions of package: their findings name the factory
+func collectFactories(funcs []*ssa.Function, res *Result) {
+	res.factories = make(map[*ssa.Function][]string)
+	for _, fn := range funcs {
+		funcsAtReturn := funcsBySignature(fn.Signature)
+		if len(funcsAtReturn) == 0 {
+			continue
+		}
+		seen := make(map[ssa.Value]bool)
+		for _, b := range fn.Blocks {
+			retInstr, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return)
+			if !ok {
+				continue
+			}
+			for _, i := range funcsAtReturn {
+				for _, v := range returnedFuncs(retInstr.Results[i], seen) {
+					if closure, ok := v.(*ssa.MakeClosure); ok {
+						anon := closure.Fn.(*ssa.Function)
+						if boundMethod(anon) == nil {
+							res.factories[anon] = append(res.factories[anon], fn.RelString(nil))
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// checkFuncs checks function values returned by factory: errors of closures become errors of factory.
+// Closures of package are checked themselves, function and method values of other packages are reported here
+func (w *walker) checkFuncs(ret *ssa.Return, v ssa.Value) {
+	pos := ret.Pos()
+	p := path{{pos, "returned"}}
+	for _, fv := range returnedFuncs(v, make(map[ssa.Value]bool)) {
+		w.graph.addEdge(ret, fv, "return-func")
+		current := w.current
+		w.current = fv
+		fvPos := retPos(fv, pos)
+		if _, ok := fv.(*ssa.Function); ok {
+			// position of function is its declaration
+			fvPos = pos
+		}
+		w.checkFunc(fv, fvPos, p)
+		w.current = current
+	}
+}
+
+func (w *walker) checkFunc(v ssa.Value, pos token.Pos, p path) {
+	var target *ssa.Function
+	switch v := v.(type) {
+	case *ssa.MakeClosure:
+		target = v.Fn.(*ssa.Function)
+		if method := boundMethod(target); method != nil {
+			// method value like db.Close
+			target = w.fn.Prog.FuncValue(method)
+			if target == nil {
+				// method of interface
+				pkgName := method.Pkg().Path()
+				w.addSource(ErrorSource{Unknown: "method " + method.FullName()})
+				if pkgName == w.pass.Pkg.Path() || isOurPkg(pkgName, w.cfg) {
+					w.allowed("OurPackages", "method of our pkg %s", pkgName)
+					return
+				}
+				w.reportf(RuleForeignCall, pos, p, method.FullName(), "error not from our pkg: %s (returned method value %s)", pkgName, method.FullName())
+				return
+			}
+		}
+	case *ssa.Function:
+		target = v
+	default:
+		w.addSource(ErrorSource{Unknown: "function value " + v.String()})
+		w.reportf(RuleDynamicCall, pos, p, v.String(), "cant check errors of returned function value %v", v)
+		return
+	}
+	w.addCallSource(target, false)
+	pkgName := funcPkgPath(target)
+	if pkgName == w.pass.Pkg.Path() || isOurPkg(pkgName, w.cfg) {
+		// closures of analyzed package are ours even if it is not in OurPackages
+		w.allowed("OurPackages", "function of our pkg %s", pkgName)
+		return
+	}
+	w.reportf(RuleForeignCall, pos, p, target.String(), "error not from our pkg: %s (returned function value %s)", pkgName, target.String())
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+// baseline stores known findings to report only new ones.
+// Entries are keyed by function, origin and rule, not by lines,
+// so baseline survives unrelated edits
+type baseline struct {
+	Entries []baselineEntry
+}
+
+type baselineEntry struct {
+	Func   string
+	Origin string
+	Rule   string
+	Count  int // number of such findings in the function
+}
+
+type baselineKey struct {
+	Func, Origin, Rule string
+}
+
+func (e baselineEntry) key() baselineKey {
+	return baselineKey{Func: e.Func, Origin: e.Origin, Rule: e.Rule}
+}
+
+func issueKey(is issue) baselineKey {
+	return baselineKey{Func: is.Func, Origin: is.Origin, Rule: is.Rule}
+}
+
+func newBaseline(issues []issue) *baseline {
+	count := make(map[baselineKey]int)
+	for _, is := range issues {
+		count[issueKey(is)]++
+	}
+	res := &baseline{Entries: make([]baselineEntry, 0, len(count))}
+	for key, n := range count {
+		res.Entries = append(res.Entries, baselineEntry{Func: key.Func, Origin: key.Origin, Rule: key.Rule, Count: n})
+	}
+	sortEntries(res.Entries)
+	return res
+}
+
+func sortEntries(entries []baselineEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Func != entries[j].Func {
+			return entries[i].Func < entries[j].Func
+		}
+		if entries[i].Origin != entries[j].Origin {
+			return entries[i].Origin < entries[j].Origin
+		}
+		return entries[i].Rule < entries[j].Rule
+	})
+}
+
+func readBaseline(name string) (*baseline, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var res baseline
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("bad baseline %s: %v", name, err)
+	}
+	return &res, nil
+}
+
+func writeBaseline(name string, b *baseline) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, append(data, '\n'), 0644)
+}
+
+// filter returns issues not covered by baseline and baseline entries that are fixed
+// (fixed entry has Count of findings that are gone)
+func (b *baseline) filter(issues []issue) (newIssues []issue, fixed []baselineEntry) {
+	left := make(map[baselineKey]int, len(b.Entries))
+	for _, e := range b.Entries {
+		left[e.key()] += e.Count
+	}
+	for _, is := range issues {
+		key := issueKey(is)
+		if left[key] > 0 {
+			left[key]--
+			continue
+		}
+		newIssues = append(newIssues, is)
+	}
+	for key, n := range left {
+		if n > 0 {
+			fixed = append(fixed, baselineEntry{Func: key.Func, Origin: key.Origin, Rule: key.Rule, Count: n})
+		}
+	}
+	sortEntries(fixed)
+	return newIssues, fixed
+}
+
+func writeFixed(out io.Writer, fixed []baselineEntry) {
+	if len(fixed) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "%d baseline entries are fixed, rewrite baseline with -write-baseline to shrink it:\n", len(fixed))
+	for _, e := range fixed {
+		fmt.Fprintf(out, "\t%s: %s from %s (%d)\n", e.Func, e.Rule, e.Origin, e.Count)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+func baselineIssue(fn, origin string) issue {
+	return issue{Finding: linter.Finding{Rule: linter.RuleForeignCall, Func: fn, Origin: origin}}
+}
+
+func TestBaseline(t *testing.T) {
+	known := newBaseline([]issue{
+		baselineIssue("a.f", "b.F"),
+		baselineIssue("a.f", "b.F"),
+		baselineIssue("a.g", "b.G"),
+	})
+	if len(known.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", known.Entries)
+	}
+
+	newIssues, fixed := known.filter([]issue{
+		baselineIssue("a.f", "b.F"),
+		baselineIssue("a.h", "b.F"),
+	})
+	if len(newIssues) != 1 || newIssues[0].Func != "a.h" {
+		t.Errorf("unexpected new issues %+v", newIssues)
+	}
+	if len(fixed) != 2 || fixed[0].Func != "a.f" || fixed[0].Count != 1 || fixed[1].Func != "a.g" {
+		t.Errorf("unexpected fixed entries %+v", fixed)
+	}
+}
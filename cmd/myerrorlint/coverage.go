@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+// coverageCounts are numbers of error return sites by class
+type coverageCounts struct {
+	Sites        int `json:"sites"`
+	Allowed      int `json:"verified_allowed"`
+	Violation    int `json:"violation"`
+	Unverifiable int `json:"unverifiable"`
+	Unsupported  int `json:"unsupported"`
+}
+
+func (c *coverageCounts) add(class string) {
+	c.Sites++
+	switch class {
+	case linter.ClassAllowed:
+		c.Allowed++
+	case linter.ClassViolation:
+		c.Violation++
+	case linter.ClassUnverifiable:
+		c.Unverifiable++
+	case linter.ClassUnsupported:
+		c.Unsupported++
+	}
+}
+
+// verified is percent of sites verified as allowed
+func (c coverageCounts) verified() float64 {
+	if c.Sites == 0 {
+		return 100
+	}
+	return float64(c.Allowed) * 100 / float64(c.Sites)
+}
+
+type packageCoverage struct {
+	Package string `json:"package"`
+	coverageCounts
+	Funcs []funcCoverage `json:"funcs"`
+}
+
+type funcCoverage struct {
+	Func string `json:"func"`
+	coverageCounts
+}
+
+// newCoverage counts return sites per package and function in order of sites
+func newCoverage(results []packageResult) []packageCoverage {
+	res := make([]packageCoverage, 0, len(results))
+	for _, r := range results {
+		pkgCov := packageCoverage{Package: r.Pkg.PkgPath, Funcs: []funcCoverage{}}
+		funcIndex := make(map[string]int)
+		for _, site := range r.Result.Sites {
+			pkgCov.add(site.Class)
+			i, ok := funcIndex[site.Func]
+			if !ok {
+				i = len(pkgCov.Funcs)
+				funcIndex[site.Func] = i
+				pkgCov.Funcs = append(pkgCov.Funcs, funcCoverage{Func: site.Func})
+			}
+			pkgCov.Funcs[i].add(site.Class)
+		}
+		res = append(res, pkgCov)
+	}
+	return res
+}
+
+func writeCoverage(out io.Writer, format string, cov []packageCoverage) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cov)
+	case "text":
+		tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "PACKAGE/FUNCTION\tSITES\tALLOWED\tVIOLATION\tUNVERIFIABLE\tUNSUPPORTED\tVERIFIED\t")
+		for _, pkgCov := range cov {
+			writeCoverageRow(tw, pkgCov.Package, pkgCov.coverageCounts)
+			for _, fnCov := range pkgCov.Funcs {
+				writeCoverageRow(tw, "  "+fnCov.Func, fnCov.coverageCounts)
+			}
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown coverage format: %s", format)
+}
+
+func writeCoverageRow(out io.Writer, name string, c coverageCounts) {
+	fmt.Fprintf(out, "%s\t%d\t%d\t%d\t%d\t%d\t%.1f%%\t\n", name, c.Sites, c.Allowed, c.Violation, c.Unverifiable, c.Unsupported, c.verified())
+}
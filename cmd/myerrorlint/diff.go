@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// changedLines are lines added or changed since git revision by absolute file name.
+// nil lines mean the whole file is new
+type changedLines map[string]map[int]bool
+
+// gitChangedLines returns lines of working tree changed since rev including untracked files
+func gitChangedLines(rev string) (changedLines, error) {
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	rootDir := strings.TrimSpace(string(root))
+	// prefixes are explicit: diff.noprefix and diff.mnemonicPrefix change them
+	diff, err := git("diff", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/", "-U0", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	res, err := parseDiff(rootDir, bytes.NewReader(diff))
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git("ls-files", "-z", "--others", "--exclude-standard", "--full-name", rootDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(string(untracked), "\x00") {
+		if name != "" {
+			res[filepath.Join(rootDir, filepath.FromSlash(name))] = nil
+		}
+	}
+	return res, nil
+}
+
+func git(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// parseDiff parses unified diff with paths relative to root (like "git diff -U0" output).
+// New file names must have "b/" prefix, names with special chars may be quoted like git does
+func parseDiff(root string, diff io.Reader) (changedLines, error) {
+	res := make(changedLines)
+	var lines map[int]bool
+	scanner := bufio.NewScanner(diff)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			// git appends tab to names with spaces
+			name := strings.TrimSuffix(strings.TrimPrefix(line, "+++ "), "\t")
+			if name == "/dev/null" {
+				// file is deleted
+				lines = nil
+				continue
+			}
+			if strings.HasPrefix(name, `"`) {
+				unquoted, err := strconv.Unquote(name)
+				if err != nil {
+					return nil, fmt.Errorf("bad file name in diff: %s", name)
+				}
+				name = unquoted
+			}
+			name = strings.TrimPrefix(name, "b/")
+			lines = make(map[int]bool)
+			res[filepath.Join(root, filepath.FromSlash(name))] = lines
+		case strings.HasPrefix(line, "@@ ") && lines != nil:
+			start, count, err := parseHunk(line)
+			if err != nil {
+				return nil, err
+			}
+			for i := start; i < start+count; i++ {
+				lines[i] = true
+			}
+		}
+	}
+	return res, scanner.Err()
+}
+
+// parseHunk returns range of new lines from hunk header like "@@ -10,2 +12,3 @@ func f() {"
+func parseHunk(header string) (start, count int, err error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, fmt.Errorf("bad hunk header: %s", header)
+	}
+	newRange := strings.SplitN(strings.TrimPrefix(fields[2], "+"), ",", 2)
+	if start, err = strconv.Atoi(newRange[0]); err != nil {
+		return 0, 0, fmt.Errorf("bad hunk header: %s", header)
+	}
+	count = 1
+	if len(newRange) == 2 {
+		if count, err = strconv.Atoi(newRange[1]); err != nil {
+			return 0, 0, fmt.Errorf("bad hunk header: %s", header)
+		}
+	}
+	return start, count, nil
+}
+
+func (c changedLines) changed(filename string, line int) bool {
+	lines, ok := c[filename]
+	return ok && (lines == nil || lines[line])
+}
+
+// touches checks if origin of issue or any step of its path is changed
+func (c changedLines) touches(is issue) bool {
+	if c.changed(is.Position.Filename, is.Position.Line) {
+		return true
+	}
+	for _, s := range is.Steps {
+		if c.changed(s.Position.Filename, s.Position.Line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c changedLines) filter(issues []issue) []issue {
+	var res []issue
+	for _, is := range issues {
+		if c.touches(is) {
+			res = append(res, is)
+		}
+	}
+	return res
+}
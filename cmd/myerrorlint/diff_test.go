@@ -0,0 +1,66 @@
+package main
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const testDiff = `diff --git a/svc/svc.go b/svc/svc.go
+index 1111111..2222222 100644
+--- a/svc/svc.go
++++ b/svc/svc.go
+@@ -10,0 +11,2 @@ func Get() error {
++	err = ext.F()
++	_ = err
+@@ -40 +42 @@ func Put() error {
+-	return nil
++	return err
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+-package svc
+diff --git a/svc/new file.go b/svc/new file.go
+--- a/svc/new file.go	
++++ b/svc/new file.go	
+@@ -1,0 +2 @@
++var x = 1
+diff --git "a/svc/\303\251.go" "b/svc/\303\251.go"
+--- "a/svc/\303\251.go"
++++ "b/svc/\303\251.go"
+@@ -3,0 +4 @@
++var y = 2
+`
+
+func TestParseDiff(t *testing.T) {
+	changed, err := parseDiff("/repo", strings.NewReader(testDiff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []int{11, 12, 42} {
+		if !changed.changed("/repo/svc/svc.go", line) {
+			t.Errorf("line %d should be changed", line)
+		}
+	}
+	if !changed.changed("/repo/svc/new file.go", 2) || !changed.changed("/repo/svc/é.go", 4) {
+		t.Errorf("quoted and spaced file names are not parsed: %v", changed)
+	}
+	if changed.changed("/repo/svc/svc.go", 13) || changed.changed("/repo/old.go", 1) {
+		t.Errorf("unexpected changed lines %v", changed)
+	}
+
+	// old error leaks via new return
+	is := issue{
+		Position: token.Position{Filename: "/repo/svc/svc.go", Line: 5},
+		Steps:    []issueStep{{Position: token.Position{Filename: "/repo/svc/svc.go", Line: 42}, Message: "returned"}},
+	}
+	if !changed.touches(is) {
+		t.Errorf("issue with changed return should be reported")
+	}
+	is.Steps = nil
+	if changed.touches(is) {
+		t.Errorf("issue on unchanged lines should not be reported")
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+// names of files written next to package with -write
+const (
+	docsMarkdownFile = "ERRORS.md"
+	docsJSONFile     = "errors.json"
+)
+
+// docsMain runs "myerrorlint docs [-config cfg.json] [-format markdown|json] [-write | -check] packages..."
+func docsMain(args []string) error {
+	flags := flag.NewFlagSet("docs", flag.ExitOnError)
+	configFile := flags.String("config", "", "JSON file with linter config")
+	format := flags.String("format", "markdown", "output format: markdown or json")
+	write := flags.Bool("write", false, "write docs to "+docsMarkdownFile+" (or "+docsJSONFile+") in directory of every package")
+	check := flags.Bool("check", false, "check that docs written to package directories are up to date")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: myerrorlint docs [-config cfg.json] [-format markdown|json] [-write | -check] packages...\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *format != "markdown" && *format != "json" {
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+	cfg, err := readConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	pkgs, err := loadPackages(flags.Args())
+	if err != nil {
+		return err
+	}
+	results, err := analyze(pkgs, linter.NewAnalyzer(cfg))
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	for _, r := range results {
+		var buf bytes.Buffer
+		if err := writeDocs(&buf, *format, packageDocs(r.Pkg.PkgPath, r.Result.Funcs)); err != nil {
+			return err
+		}
+		if !*write && !*check {
+			os.Stdout.Write(buf.Bytes())
+			continue
+		}
+		if len(r.Pkg.GoFiles) == 0 {
+			continue
+		}
+		name := docsMarkdownFile
+		if *format == "json" {
+			name = docsJSONFile
+		}
+		name = filepath.Join(filepath.Dir(r.Pkg.GoFiles[0]), name)
+		if *check {
+			old, err := ioutil.ReadFile(name)
+			if err != nil || !bytes.Equal(old, buf.Bytes()) {
+				stale = append(stale, name)
+			}
+			continue
+		}
+		if err := ioutil.WriteFile(name, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("error docs are out of date: %s", strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+// docsPackage is error contract of exported API of package
+type docsPackage struct {
+	Package string     `json:"package"`
+	Funcs   []docsFunc `json:"funcs"`
+}
+
+type docsFunc struct {
+	Name   string      `json:"name"`
+	Errors []docsError `json:"errors"`
+}
+
+type docsError struct {
+	Type     string   `json:"type,omitempty"`
+	Sentinel string   `json:"sentinel,omitempty"`
+	Unknown  string   `json:"unknown,omitempty"`
+	Wrapped  bool     `json:"wrapped,omitempty"`
+	Via      []string `json:"via,omitempty"`
+}
+
+// packageDocs collects error sets of exported functions and methods.
+// Names of the package itself are written without package path.
+// Positions are not included so docs change only when errors change
+func packageDocs(pkgPath string, funcs []linter.FuncErrors) docsPackage {
+	rel := func(name string) string {
+		return strings.Replace(name, pkgPath+".", "", -1)
+	}
+	docs := docsPackage{Package: pkgPath, Funcs: []docsFunc{}}
+	for _, fn := range funcs {
+		if !fn.Exported {
+			continue
+		}
+		df := docsFunc{Name: rel(fn.Func), Errors: []docsError{}}
+		for _, src := range fn.Errors {
+			de := docsError{Type: rel(src.Type), Sentinel: rel(src.Sentinel), Unknown: rel(src.Unknown), Wrapped: src.Wrapped}
+			for _, via := range src.Via {
+				de.Via = append(de.Via, rel(via))
+			}
+			df.Errors = append(df.Errors, de)
+		}
+		docs.Funcs = append(docs.Funcs, df)
+	}
+	sort.SliceStable(docs.Funcs, func(i, j int) bool {
+		return docs.Funcs[i].Name < docs.Funcs[j].Name
+	})
+	return docs
+}
+
+func writeDocs(out io.Writer, format string, docs docsPackage) error {
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(docs)
+	}
+	writeDocsMarkdown(out, docs)
+	return nil
+}
+
+func writeDocsMarkdown(out io.Writer, docs docsPackage) {
+	fmt.Fprintf(out, "# Errors of package %s\n\n", docs.Package)
+	fmt.Fprintf(out, "Generated by `myerrorlint docs`, do not edit.\n")
+	for _, fn := range docs.Funcs {
+		fmt.Fprintf(out, "\n## %s\n\n", fn.Name)
+		if len(fn.Errors) == 0 {
+			fmt.Fprintf(out, "Returns only nil error.\n")
+			continue
+		}
+		for _, e := range fn.Errors {
+			var item string
+			switch {
+			case e.Type != "":
+				item = fmt.Sprintf("`%s`", e.Type)
+			case e.Sentinel != "":
+				item = fmt.Sprintf("`%s`", e.Sentinel)
+			default:
+				item = fmt.Sprintf("unknown: `%s`", e.Unknown)
+			}
+			var notes []string
+			if e.Wrapped {
+				notes = append(notes, "wrapped")
+			}
+			if len(e.Via) > 0 {
+				notes = append(notes, "via `"+strings.Join(e.Via, "` -> `")+"`")
+			}
+			if len(notes) > 0 {
+				item += " (" + strings.Join(notes, ", ") + ")"
+			}
+			fmt.Fprintf(out, "- %s\n", item)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+func TestDocs(t *testing.T) {
+	funcs := []linter.FuncErrors{
+		{Func: "example.com/svc.helper", Errors: []linter.ErrorSource{{Type: "*example.com/svc.NotFound"}}},
+		{Func: "(*example.com/svc.Repo).Get", Exported: true, Errors: []linter.ErrorSource{
+			{Type: "*example.com/svc.NotFound", Via: []string{"example.com/svc.helper"}},
+			{Sentinel: "io.EOF", Wrapped: true},
+		}},
+		{Func: "example.com/svc.Close", Exported: true},
+	}
+	docs := packageDocs("example.com/svc", funcs)
+	if len(docs.Funcs) != 2 || docs.Funcs[0].Name != "(*Repo).Get" || docs.Funcs[1].Name != "Close" {
+		t.Fatalf("unexpected funcs %+v", docs.Funcs)
+	}
+
+	var out bytes.Buffer
+	writeDocsMarkdown(&out, docs)
+	for _, line := range []string{
+		"## (*Repo).Get\n",
+		"- `*NotFound` (via `helper`)\n",
+		"- `io.EOF` (wrapped)\n",
+		"## Close\n\nReturns only nil error.\n",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("no %q in docs:\n%s", line, out.String())
+		}
+	}
+}
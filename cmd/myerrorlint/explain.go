@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+// explainMain runs "myerrorlint explain [-config cfg.json] path/file.go:LINE"
+func explainMain(args []string) error {
+	flags := flag.NewFlagSet("explain", flag.ExitOnError)
+	configFile := flags.String("config", "", "JSON file with linter config")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: myerrorlint explain [-config cfg.json] path/file.go:LINE\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	file, line, err := parseFileLine(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	cfg, err := readConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	// entry matches files by absolute name: argument may be relative
+	cfg.GraphFuncs = append(cfg.GraphFuncs, file+":"+strconv.Itoa(line))
+	// load only package of the file
+	pkgs, err := loadPackages([]string{"file=" + file})
+	if err != nil {
+		return err
+	}
+	results, err := analyze(pkgs, linter.NewAnalyzer(cfg))
+	if err != nil {
+		return err
+	}
+	fset, graphs := graphsOf(results)
+	if len(graphs) == 0 {
+		return fmt.Errorf("no function returning error at %s", flags.Arg(0))
+	}
+	for _, g := range graphs {
+		if err := writeExplain(os.Stdout, fset, &cfg, g, file, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseFileLine(s string) (string, int, error) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return "", 0, fmt.Errorf("expected file.go:LINE, got %s", s)
+	}
+	line, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("expected file.go:LINE, got %s", s)
+	}
+	file, err := filepath.Abs(s[:i])
+	if err != nil {
+		return "", 0, err
+	}
+	return file, line, nil
+}
+
+// writeExplain prints walk of returns at line or of returns that reach values at line (origins) as tree
+func writeExplain(out io.Writer, fset *token.FileSet, cfg *linter.Config, g *linter.Graph, file string, line int) error {
+	children := make(map[int][]linter.GraphEdge)
+	hasParent := make(map[int]bool)
+	for _, e := range g.Edges {
+		children[e.From] = append(children[e.From], e)
+		hasParent[e.To] = true
+	}
+	atLine := make(map[int]bool)
+	var roots, returnsAtLine []int
+	for _, n := range g.Nodes {
+		if !hasParent[n.ID] {
+			roots = append(roots, n.ID)
+		}
+		if position := fset.Position(n.Pos); n.Pos.IsValid() && position.Line == line && position.Filename == file {
+			atLine[n.ID] = true
+			if !hasParent[n.ID] {
+				returnsAtLine = append(returnsAtLine, n.ID)
+			}
+		}
+	}
+	if len(atLine) == 0 {
+		return fmt.Errorf("no return or origin of error at %s:%d in %s", file, line, g.Func)
+	}
+
+	// nodes on the way to values at line
+	relevant := make(map[int]bool)
+	if len(returnsAtLine) > 0 {
+		roots = returnsAtLine
+		for id := range g.Nodes {
+			relevant[id] = true
+		}
+	} else {
+		var reaches func(id int, onPath map[int]bool) bool
+		reaches = func(id int, onPath map[int]bool) bool {
+			if onPath[id] {
+				return false
+			}
+			onPath[id] = true
+			defer delete(onPath, id)
+			res := atLine[id]
+			for _, e := range children[id] {
+				if reaches(e.To, onPath) {
+					res = true
+				}
+			}
+			if res {
+				relevant[id] = true
+			}
+			return res
+		}
+		for _, root := range roots {
+			reaches(root, make(map[int]bool))
+		}
+	}
+
+	fmt.Fprintf(out, "%s:\n", g.Func)
+	var walk func(id int, edge string, indent string, onPath map[int]bool)
+	walk = func(id int, edge string, indent string, onPath map[int]bool) {
+		n := g.Nodes[id]
+		text := n.Label
+		if edge != "" {
+			text = edge + ": " + text
+		}
+		if n.Pos.IsValid() {
+			position := fset.Position(n.Pos)
+			text += fmt.Sprintf("  (%s:%d)", filepath.Base(position.Filename), position.Line)
+		}
+		fmt.Fprintf(out, "%s%s\n", indent, text)
+		if onPath[id] {
+			fmt.Fprintf(out, "%s  (already followed)\n", indent)
+			return
+		}
+		if n.Verdict != "" {
+			for _, s := range explainLeaf(cfg, n) {
+				fmt.Fprintf(out, "%s  => %s\n", indent, s)
+			}
+		}
+		onPath[id] = true
+		defer delete(onPath, id)
+		for _, e := range children[id] {
+			if relevant[e.To] {
+				walk(e.To, e.Kind, indent+"  ", onPath)
+			}
+		}
+	}
+	for _, root := range roots {
+		if relevant[root] {
+			walk(root, "", "  ", make(map[int]bool))
+		}
+	}
+	return nil
+}
+
+// explainLeaf says why leaf got its verdict and which setting would change it
+func explainLeaf(cfg *linter.Config, n linter.GraphNode) []string {
+	if n.Verdict == linter.VerdictAllowed {
+		if n.Setting == "" {
+			return []string{"allowed: " + n.Message}
+		}
+		return []string{fmt.Sprintf("allowed: %s (by %s)", n.Message, n.Setting)}
+	}
+	res := []string{fmt.Sprintf("%s: %s", n.Verdict, n.Message)}
+	if ruleCfg, ok := cfg.Rules[n.Verdict]; ok && ruleCfg.Disabled {
+		res = append(res, "not reported: rule is disabled in Rules")
+	} else if n.Verdict == linter.RuleUnsupported && !cfg.ReportUnknown {
+		res = append(res, "may be not reported: ReportUnknown is false")
+	}
+	switch n.Verdict {
+	case linter.RuleForeignCall:
+		res = append(res, fmt.Sprintf("would be allowed: package of %s in OurPackages, or error wrapped by function from WrapFuncs (WrapFuncWithFirstArgError)", n.Origin))
+	case linter.RuleDisallowedType:
+		if n.Setting == "InterfaceExemptions" {
+			res = append(res, fmt.Sprintf("would be allowed: %s in Errors of InterfaceExemptions", n.Origin))
+			break
+		}
+		res = append(res, fmt.Sprintf("would be allowed: %s in AllowedTypes", n.Origin))
+	default:
+		res = append(res, fmt.Sprintf("would not be reported: Rules %s:off, or return value of allowed type instead", n.Verdict))
+	}
+	return res
+}
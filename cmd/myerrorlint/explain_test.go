@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"strings"
+	"testing"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+func TestExplain(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("/src/a/a.go", -1, 100)
+	file.SetLines([]int{0, 10, 20, 30, 40, 50})
+	g := &linter.Graph{
+		Func: "a.f",
+		Nodes: []linter.GraphNode{
+			{ID: 0, Label: "return t1", Pos: file.Pos(40)},
+			{ID: 1, Label: "t1 = phi [1: t0, 2: nil:error]", Pos: file.Pos(10)},
+			{ID: 2, Label: "t0 = b.F()", Pos: file.Pos(20), Verdict: linter.RuleForeignCall, Message: "error not from our pkg: b", Origin: "b.F"},
+			{ID: 3, Label: "nil:error", Verdict: linter.VerdictAllowed, Message: "nil"},
+		},
+		Edges: []linter.GraphEdge{
+			{From: 0, To: 1, Kind: "return"},
+			{From: 1, To: 2, Kind: "phi"},
+			{From: 1, To: 3, Kind: "phi"},
+		},
+	}
+	cfg := &linter.Config{}
+
+	// origin at line 3 - only branch to it is printed
+	var out bytes.Buffer
+	if err := writeExplain(&out, fset, cfg, g, "/src/a/a.go", 3); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "phi: t0 = b.F()") || strings.Contains(out.String(), "allowed: nil") {
+		t.Errorf("unexpected explanation:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "b.F in OurPackages") {
+		t.Errorf("expected hint about OurPackages:\n%s", out.String())
+	}
+
+	// return at line 5 - all branches are printed
+	out.Reset()
+	if err := writeExplain(&out, fset, cfg, g, "/src/a/a.go", 5); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "allowed: nil") {
+		t.Errorf("unexpected explanation:\n%s", out.String())
+	}
+
+	if err := writeExplain(&out, fset, cfg, g, "/src/a/a.go", 1); err == nil {
+		t.Errorf("expected error for line without values")
+	}
+}
+
+func TestExplainLeafSetting(t *testing.T) {
+	cfg := &linter.Config{}
+	leaf := linter.GraphNode{Verdict: linter.RuleDisallowedType, Message: "not our type error: *b.E", Origin: "*b.E"}
+	if hint := explainLeaf(cfg, leaf); !strings.Contains(hint[len(hint)-1], "in AllowedTypes") {
+		t.Errorf("expected hint about AllowedTypes: %v", hint)
+	}
+	leaf.Setting = "InterfaceExemptions"
+	if hint := explainLeaf(cfg, leaf); !strings.Contains(hint[len(hint)-1], "InterfaceExemptions") {
+		t.Errorf("expected hint about InterfaceExemptions: %v", hint)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"strings"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+type jsonGraph struct {
+	Func  string          `json:"func"`
+	Nodes []jsonGraphNode `json:"nodes"`
+	Edges []jsonGraphEdge `json:"edges"`
+}
+
+type jsonGraphEdge struct {
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	Kind string `json:"kind"`
+}
+
+type jsonGraphNode struct {
+	ID       int    `json:"id"`
+	Label    string `json:"label"`
+	Kind     string `json:"kind"`
+	Position string `json:"position,omitempty"`
+	Verdict  string `json:"verdict,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+func graphsOf(results []packageResult) (fset *token.FileSet, graphs []*linter.Graph) {
+	for _, r := range results {
+		fset = r.Pkg.Fset
+		graphs = append(graphs, r.Result.Graphs...)
+	}
+	return fset, graphs
+}
+
+func writeGraphs(out io.Writer, format string, fset *token.FileSet, graphs []*linter.Graph) error {
+	switch format {
+	case "dot":
+		for _, g := range graphs {
+			writeDOT(out, fset, g)
+		}
+		return nil
+	case "json":
+		res := make([]jsonGraph, 0, len(graphs))
+		for _, g := range graphs {
+			jg := jsonGraph{Func: g.Func}
+			for _, e := range g.Edges {
+				jg.Edges = append(jg.Edges, jsonGraphEdge{From: e.From, To: e.To, Kind: e.Kind})
+			}
+			for _, n := range g.Nodes {
+				jn := jsonGraphNode{ID: n.ID, Label: n.Label, Kind: n.Kind, Verdict: n.Verdict, Message: n.Message}
+				if n.Pos.IsValid() {
+					jn.Position = fset.Position(n.Pos).String()
+				}
+				jg.Nodes = append(jg.Nodes, jn)
+			}
+			res = append(res, jg)
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	return fmt.Errorf("unknown graph format: %s", format)
+}
+
+func writeDOT(out io.Writer, fset *token.FileSet, g *linter.Graph) {
+	fmt.Fprintf(out, "digraph %q {\n", g.Func)
+	fmt.Fprintf(out, "\tnode [shape=box fontname=monospace];\n")
+	for _, n := range g.Nodes {
+		lines := []string{n.Label, n.Kind}
+		if n.Pos.IsValid() {
+			lines = append(lines, fset.Position(n.Pos).String())
+		}
+		attrs := ""
+		switch n.Verdict {
+		case "":
+		case linter.VerdictAllowed:
+			lines = append(lines, "allowed: "+n.Message)
+			attrs = " color=green"
+		default:
+			lines = append(lines, n.Verdict+": "+n.Message)
+			attrs = " color=red"
+		}
+		fmt.Fprintf(out, "\tn%d [label=%q%s];\n", n.ID, strings.Join(lines, "\n"), attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(out, "\tn%d -> n%d [label=%q];\n", e.From, e.To, e.Kind)
+	}
+	fmt.Fprintf(out, "}\n")
+}
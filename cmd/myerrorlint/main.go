@@ -0,0 +1,191 @@
+// myerrorlint is a standalone command for myerrorlint analyzer
+//
+// Usage:
+//
+//	myerrorlint [-config cfg.json] [-format text|sarif] [-out file] [-baseline file | -write-baseline file] packages...
+//	myerrorlint -coverage [-format text|json] packages...
+//	myerrorlint -graph pkg.Func|file.go:line [-format dot|json] packages...
+//	myerrorlint explain [-config cfg.json] path/file.go:LINE
+//	myerrorlint query [-config cfg.json] [-all] ERROR packages...
+//	myerrorlint docs [-config cfg.json] [-format markdown|json] [-write | -check] packages...
+//
+// Config file is JSON with fields of myerrorlint.Config.
+// Baseline file stores known findings: with -baseline only findings not in it are reported.
+// With -new-from-rev only findings whose origin or path touch lines changed since git revision are reported.
+// With -coverage error return sites are counted by classes per package and function instead of reporting findings.
+// With -graph values walked from returns of the function are written as Graphviz DOT or JSON.
+// explain prints why return or origin of error at the line is reported (or not) as a tree.
+// query lists exported functions that can return error type or var and calls it comes through.
+// docs generates Markdown or JSON with errors every exported function and method of package can return
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+var (
+	configFile = flag.String("config", "", "JSON file with linter config")
+	format     = flag.String("format", "", "output format: text or sarif (text or json for -coverage, dot or json for -graph)")
+	outFile    = flag.String("out", "", "write output to file instead of stdout")
+
+	baselineFile      = flag.String("baseline", "", "report only findings not stored in baseline file")
+	writeBaselineFile = flag.String("write-baseline", "", "store all findings to baseline file")
+	newFromRev        = flag.String("new-from-rev", "", "report only findings touching lines changed since git revision")
+
+	coverage = flag.Bool("coverage", false, "report how many error return sites are verified per package and function")
+	graph    = flag.String("graph", "", "write graph of error values of function selected by qualified name or file.go:line")
+)
+
+// subcommands by name
+var commands = map[string]func(args []string) error{
+	"explain": explainMain,
+	"query":   queryMain,
+	"docs":    docsMain,
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "myerrorlint: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: myerrorlint [flags] packages...\n       myerrorlint explain [-config cfg.json] path/file.go:LINE\n       myerrorlint query [-config cfg.json] [-all] ERROR packages...\n       myerrorlint docs [-config cfg.json] [-format markdown|json] [-write | -check] packages...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	found, err := run(flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "myerrorlint: %v\n", err)
+		os.Exit(1)
+	}
+	if found {
+		os.Exit(3)
+	}
+}
+
+// run checks packages and writes result, returns true if there are findings
+func run(patterns []string) (bool, error) {
+	cfg, err := readConfig(*configFile)
+	if err != nil {
+		return false, err
+	}
+	if *graph != "" {
+		cfg.GraphFuncs = append(cfg.GraphFuncs, *graph)
+	}
+	pkgs, err := loadPackages(patterns)
+	if err != nil {
+		return false, err
+	}
+	results, err := analyze(pkgs, linter.NewAnalyzer(cfg))
+	if err != nil {
+		return false, err
+	}
+
+	out := io.Writer(os.Stdout)
+	if *outFile != "" {
+		file, err := os.Create(*outFile)
+		if err != nil {
+			return false, err
+		}
+		defer file.Close()
+		out = file
+	}
+	if *coverage {
+		return false, writeCoverage(out, formatOr("text"), newCoverage(results))
+	}
+	if *graph != "" {
+		fset, graphs := graphsOf(results)
+		if len(graphs) == 0 {
+			return false, fmt.Errorf("no function returning error found for %s", *graph)
+		}
+		return false, writeGraphs(out, formatOr("dot"), fset, graphs)
+	}
+
+	issues := issuesOf(results)
+	if *writeBaselineFile != "" {
+		return false, writeBaseline(*writeBaselineFile, newBaseline(issues))
+	}
+	if *baselineFile != "" {
+		known, err := readBaseline(*baselineFile)
+		if err != nil {
+			return false, err
+		}
+		var fixed []baselineEntry
+		issues, fixed = known.filter(issues)
+		writeFixed(os.Stderr, fixed)
+	}
+	if *newFromRev != "" {
+		changed, err := gitChangedLines(*newFromRev)
+		if err != nil {
+			return false, err
+		}
+		issues = changed.filter(issues)
+	}
+
+	switch formatOr("text") {
+	case "text":
+		writeText(out, issues)
+	case "sarif":
+		if err := writeSARIF(out, issues); err != nil {
+			return false, err
+		}
+	default:
+		return false, fmt.Errorf("unknown format: %s", formatOr("text"))
+	}
+	return len(issues) > 0, nil
+}
+
+// formatOr returns -format or default format of mode
+func formatOr(def string) string {
+	if *format == "" {
+		return def
+	}
+	return *format
+}
+
+func readConfig(name string) (linter.Config, error) {
+	var cfg linter.Config
+	if name == "" {
+		return cfg, nil
+	}
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("bad config %s: %v", name, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("bad config %s: %v", name, err)
+	}
+	return cfg, nil
+}
+
+func writeText(out io.Writer, issues []issue) {
+	for _, is := range issues {
+		prefix := ""
+		if is.Severity == linter.SeverityWarning {
+			prefix = "[warn] "
+		}
+		fmt.Fprintf(out, "%s: %s%s [%s]\n", is.Position, prefix, is.Message, is.Rule)
+		for _, s := range is.Steps {
+			fmt.Fprintf(out, "\t%s: %s\n", s.Position, s.Message)
+		}
+	}
+}
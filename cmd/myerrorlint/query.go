@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+// queryMain runs "myerrorlint query [-config cfg.json] [-all] ERROR packages..."
+func queryMain(args []string) error {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	configFile := flags.String("config", "", "JSON file with linter config")
+	all := flags.Bool("all", false, "list unexported functions too")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: myerrorlint query [-config cfg.json] [-all] ERROR packages...\n")
+		fmt.Fprintf(os.Stderr, "ERROR is error type (*pgconn.PgError) or error var (storage.ErrNotFound)\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	cfg, err := readConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	pkgs, err := loadPackages(flags.Args()[1:])
+	if err != nil {
+		return err
+	}
+	results, err := analyze(pkgs, linter.NewAnalyzer(cfg))
+	if err != nil {
+		return err
+	}
+	writeQuery(os.Stdout, results, flags.Arg(0), *all)
+	return nil
+}
+
+// writeQuery lists functions that can return error and chains of calls it comes through
+func writeQuery(out io.Writer, results []packageResult, query string, all bool) {
+	for _, r := range results {
+		for _, fn := range r.Result.Funcs {
+			if !fn.Exported && !all {
+				continue
+			}
+			var chains []string
+			for _, src := range fn.Errors {
+				if matchError(src, query) {
+					chains = append(chains, errorChain(fn.Func, src))
+				}
+			}
+			if len(chains) == 0 {
+				continue
+			}
+			fmt.Fprintf(out, "%s (%s)\n", fn.Func, shortPosition(r.Pkg.Fset, fn.Pos))
+			for _, chain := range chains {
+				fmt.Fprintf(out, "\t%s\n", chain)
+			}
+		}
+	}
+}
+
+func shortPosition(fset *token.FileSet, pos token.Pos) string {
+	position := fset.Position(pos)
+	return fmt.Sprintf("%s:%d", filepath.Base(position.Filename), position.Line)
+}
+
+func errorChain(fn string, src linter.ErrorSource) string {
+	items := append([]string{fn}, src.Via...)
+	items = append(items, src.String())
+	chain := strings.Join(items, " -> ")
+	if src.Wrapped {
+		chain += " (wrapped)"
+	}
+	return chain
+}
+
+// matchError checks if source is error type or var from query.
+// Query may use full package path or only package name
+func matchError(src linter.ErrorSource, query string) bool {
+	for _, name := range []string{src.Type, src.Sentinel} {
+		if name != "" && (name == query || shortTypeName(name) == query) {
+			return true
+		}
+	}
+	return false
+}
+
+// shortTypeName strips package path: "*github.com/jackc/pgconn.PgError" -> "*pgconn.PgError"
+func shortTypeName(name string) string {
+	prefix := name[:len(name)-len(strings.TrimLeft(name, "*"))]
+	rest := name[len(prefix):]
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		rest = rest[i+1:]
+	}
+	return prefix + rest
+}
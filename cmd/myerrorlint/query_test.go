@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+func TestMatchError(t *testing.T) {
+	tests := []struct {
+		src   linter.ErrorSource
+		query string
+		match bool
+	}{
+		{linter.ErrorSource{Type: "*github.com/jackc/pgconn.PgError"}, "*pgconn.PgError", true},
+		{linter.ErrorSource{Type: "*github.com/jackc/pgconn.PgError"}, "*github.com/jackc/pgconn.PgError", true},
+		{linter.ErrorSource{Type: "*github.com/jackc/pgconn.PgError"}, "pgconn.PgError", false},
+		{linter.ErrorSource{Sentinel: "example.com/svc/storage.ErrNotFound"}, "storage.ErrNotFound", true},
+		{linter.ErrorSource{Unknown: "storage.ErrNotFound"}, "storage.ErrNotFound", false},
+	}
+	for _, tt := range tests {
+		if got := matchError(tt.src, tt.query); got != tt.match {
+			t.Errorf("matchError(%+v, %q) = %v", tt.src, tt.query, got)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	linter "github.com/Rikkuru/myerrorlint"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// issue is a finding with resolved positions
+type issue struct {
+	linter.Finding
+	Position token.Position
+	Steps    []issueStep // provenance from origin to return
+}
+
+type issueStep struct {
+	Position token.Position
+	Message  string
+}
+
+// loadPackages loads packages matching patterns with syntax and types
+func loadPackages(patterns []string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	var errs []packages.Error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		errs = append(errs, pkg.Errors...)
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to load packages: %v", errs[0])
+	}
+	return pkgs, nil
+}
+
+// packageResult is a result of analyzer for one package
+type packageResult struct {
+	Pkg    *packages.Package
+	Result *linter.Result
+}
+
+// analyze runs analyzer with analyzers it requires on every package.
+// Analyzers are run on not standard dependencies first to get facts about them
+func analyze(pkgs []*packages.Package, analyzer *analysis.Analyzer) ([]packageResult, error) {
+	initial := make(map[*packages.Package]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		initial[pkg] = true
+	}
+	var order []*packages.Package
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if initial[pkg] || !isStdlib(pkg.PkgPath) {
+			order = append(order, pkg)
+		}
+	})
+
+	facts := make(factStore)
+	var results []packageResult
+	for _, pkg := range order {
+		res, err := runAnalyzer(pkg, analyzer, facts, make(map[*analysis.Analyzer]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		if initial[pkg] {
+			results = append(results, packageResult{Pkg: pkg, Result: res.(*linter.Result)})
+		}
+	}
+	return results, nil
+}
+
+// runAnalyzer runs analyzer on package after analyzers it requires
+// done - results of analyzers already run on the package
+func runAnalyzer(pkg *packages.Package, analyzer *analysis.Analyzer, facts factStore, done map[*analysis.Analyzer]interface{}) (interface{}, error) {
+	if res, ok := done[analyzer]; ok {
+		return res, nil
+	}
+	resultOf := make(map[*analysis.Analyzer]interface{}, len(analyzer.Requires))
+	for _, req := range analyzer.Requires {
+		res, err := runAnalyzer(pkg, req, facts, done)
+		if err != nil {
+			return nil, err
+		}
+		resultOf[req] = res
+	}
+	res, err := analyzer.Run(newPass(pkg, analyzer, resultOf, facts))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %v", pkg.PkgPath, analyzer.Name, err)
+	}
+	done[analyzer] = res
+	return res, nil
+}
+
+// isStdlib checks if package is from standard library: it has no domain in path
+func isStdlib(pkgPath string) bool {
+	first := strings.SplitN(pkgPath, "/", 2)[0]
+	return !strings.Contains(first, ".")
+}
+
+type factKey struct {
+	obj types.Object
+	pkg *types.Package
+	t   reflect.Type
+}
+
+// factStore keeps facts of all analyzed packages
+type factStore map[factKey]analysis.Fact
+
+func (s factStore) get(key factKey, fact analysis.Fact) bool {
+	stored, ok := s[key]
+	if ok {
+		reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+	}
+	return ok
+}
+
+func issuesOf(results []packageResult) []issue {
+	var issues []issue
+	for _, r := range results {
+		for _, f := range r.Result.Findings {
+			issues = append(issues, resolve(r.Pkg.Fset, f))
+		}
+	}
+	return issues
+}
+
+func newPass(pkg *packages.Package, analyzer *analysis.Analyzer, resultOf map[*analysis.Analyzer]interface{}, facts factStore) *analysis.Pass {
+	return &analysis.Pass{
+		Analyzer:   analyzer,
+		Fset:       pkg.Fset,
+		Files:      pkg.Syntax,
+		OtherFiles: pkg.OtherFiles,
+		Pkg:        pkg.Types,
+		TypesInfo:  pkg.TypesInfo,
+		TypesSizes: pkg.TypesSizes,
+		ResultOf:   resultOf,
+		// findings are taken from analyzer result
+		Report: func(analysis.Diagnostic) {},
+		ImportObjectFact: func(obj types.Object, fact analysis.Fact) bool {
+			return facts.get(factKey{obj: obj, t: reflect.TypeOf(fact)}, fact)
+		},
+		ExportObjectFact: func(obj types.Object, fact analysis.Fact) {
+			facts[factKey{obj: obj, t: reflect.TypeOf(fact)}] = fact
+		},
+		ImportPackageFact: func(pkg *types.Package, fact analysis.Fact) bool {
+			return facts.get(factKey{pkg: pkg, t: reflect.TypeOf(fact)}, fact)
+		},
+		ExportPackageFact: func(fact analysis.Fact) {
+			facts[factKey{pkg: pkg.Types, t: reflect.TypeOf(fact)}] = fact
+		},
+	}
+}
+
+func resolve(fset *token.FileSet, f linter.Finding) issue {
+	res := issue{Finding: f, Position: fset.Position(f.Pos)}
+	for _, r := range f.Related {
+		res.Steps = append(res.Steps, issueStep{Position: fset.Position(r.Pos), Message: r.Message})
+	}
+	return res
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+// SARIF 2.1.0 subset we produce
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	HelpURI              string             `json:"helpUri"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	CodeFlows           []sarifCodeFlow   `json:"codeFlows,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifCodeFlow struct {
+	ThreadFlows []sarifThreadFlow `json:"threadFlows"`
+}
+
+type sarifThreadFlow struct {
+	Locations []sarifThreadFlowLocation `json:"locations"`
+}
+
+type sarifThreadFlowLocation struct {
+	Location sarifLocation `json:"location"`
+}
+
+const fingerprintKey = "myerrorlint/v1"
+
+func sarifLevel(severity linter.Severity) string {
+	switch severity {
+	case linter.SeverityWarning:
+		return "warning"
+	case linter.SeverityOff:
+		return "none"
+	}
+	return "error"
+}
+
+func writeSARIF(out io.Writer, issues []issue) error {
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "myerrorlint",
+			InformationURI: "https://github.com/Rikkuru/myerrorlint",
+		}},
+		Results: []sarifResult{},
+	}
+	ruleIndex := make(map[string]int, len(linter.Rules))
+	for i, rule := range linter.Rules {
+		ruleIndex[rule.ID] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Doc},
+			HelpURI:              rule.URL,
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+		})
+	}
+	fingerprints := fingerprints(issues)
+	for i, is := range issues {
+		result := sarifResult{
+			RuleID:              is.Rule,
+			RuleIndex:           ruleIndex[is.Rule],
+			Level:               sarifLevel(is.Severity),
+			Message:             sarifMessage{Text: is.Message},
+			Locations:           []sarifLocation{sarifLocationOf(root, is.Position, "")},
+			PartialFingerprints: map[string]string{fingerprintKey: fingerprints[i]},
+		}
+		if len(is.Steps) > 0 {
+			flow := sarifThreadFlow{}
+			for _, s := range is.Steps {
+				flow.Locations = append(flow.Locations, sarifThreadFlowLocation{Location: sarifLocationOf(root, s.Position, s.Message)})
+			}
+			result.CodeFlows = []sarifCodeFlow{{ThreadFlows: []sarifThreadFlow{flow}}}
+		}
+		run.Results = append(run.Results, result)
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func sarifLocationOf(root string, pos token.Position, message string) sarifLocation {
+	uri := pos.Filename
+	if rel, err := filepath.Rel(root, pos.Filename); err == nil {
+		uri = rel
+	}
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(uri), URIBaseID: "%SRCROOT%"},
+		Region:           sarifRegion{StartLine: pos.Line, StartColumn: pos.Column},
+	}}
+	if message != "" {
+		loc.Message = &sarifMessage{Text: message}
+	}
+	return loc
+}
+
+// fingerprints returns fingerprint for every issue
+// They do not depend on lines so stay the same when unrelated code is edited:
+// hash of rule, file, function and origin plus number of such issue in the function.
+// Message is not hashed: unsupported cases describe SSA values in it
+func fingerprints(issues []issue) []string {
+	res := make([]string, len(issues))
+	count := make(map[string]int)
+	for i, is := range issues {
+		key := fmt.Sprintf("%s\x00%s\x00%s\x00%s", is.Rule, filepath.Base(is.Position.Filename), is.Func, is.Origin)
+		hash := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, count[key])))
+		count[key]++
+		res[i] = hex.EncodeToString(hash[:16])
+	}
+	return res
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"testing"
+
+	linter "github.com/Rikkuru/myerrorlint"
+)
+
+func testIssue(line int) issue {
+	return issue{
+		Finding: linter.Finding{
+			Rule:     linter.RuleForeignCall,
+			Severity: linter.SeverityError,
+			Message:  "error not from our pkg: b",
+			Func:     "a.f",
+			Origin:   "b.F",
+		},
+		Position: token.Position{Filename: "a.go", Line: line, Column: 2},
+		Steps: []issueStep{
+			{Position: token.Position{Filename: "a.go", Line: line, Column: 2}, Message: "assigned"},
+			{Position: token.Position{Filename: "a.go", Line: line + 3, Column: 2}, Message: "returned"},
+		},
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSARIF(&buf, []issue{testIssue(10)}); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(linter.Rules) {
+		t.Errorf("expected %d rules, got %d", len(linter.Rules), len(run.Tool.Driver.Rules))
+	}
+	result := run.Results[0]
+	if run.Tool.Driver.Rules[result.RuleIndex].ID != linter.RuleForeignCall {
+		t.Errorf("bad rule index %d", result.RuleIndex)
+	}
+	flow := result.CodeFlows[0].ThreadFlows[0].Locations
+	if len(flow) != 2 || flow[1].Location.PhysicalLocation.Region.StartLine != 13 {
+		t.Errorf("unexpected code flow %+v", flow)
+	}
+}
+
+func TestFingerprints(t *testing.T) {
+	before := fingerprints([]issue{testIssue(10), testIssue(20)})
+	after := fingerprints([]issue{testIssue(15), testIssue(25)})
+	if before[0] != after[0] || before[1] != after[1] {
+		t.Errorf("fingerprints depend on lines: %v %v", before, after)
+	}
+	if before[0] == before[1] {
+		t.Errorf("same fingerprint for different issues")
+	}
+	changed := testIssue(10)
+	changed.Message = "unsupported case for error value: Field error"
+	if fingerprints([]issue{changed})[0] != before[0] {
+		t.Errorf("fingerprint depends on message")
+	}
+}
@@ -0,0 +1,313 @@
+package myerrorlint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// contractDirective declares errors function can return:
+//
+//	//myerrorlint:returns storage.NotFoundError, storage.ErrConflict
+//
+// Names are types or error vars of the package or of packages imported by the file (by their names in the file).
+// Type is recorded in form implementing error: T with methods of *T means *T
+const contractDirective = "//myerrorlint:returns"
+
+// contract is a list of errors declared by directive
+type contract struct {
+	Pos    token.Pos
+	Errors []ErrorSource // Type or Sentinel
+}
+
+// allows checks if error source is declared.
+// Type declared without pointer allows pointer to it too
+func (c *contract) allows(src ErrorSource) bool {
+	for _, e := range c.Errors {
+		switch {
+		case e.Type != "" && (src.Type == e.Type || src.Type == "*"+e.Type):
+			return true
+		case e.Sentinel != "" && src.Sentinel == e.Sentinel:
+			return true
+		}
+	}
+	return false
+}
+
+// parseContract finds directive in comments and resolves its names in scope of package and imports of file.
+// Names that can not be resolved are returned as bad
+func parseContract(pass *analysis.Pass, doc *ast.CommentGroup) (c *contract, bad []string) {
+	if doc == nil {
+		return nil, nil
+	}
+	for _, comment := range doc.List {
+		if !strings.HasPrefix(comment.Text, contractDirective) {
+			continue
+		}
+		c = &contract{Pos: comment.Pos()}
+		imports := fileImports(pass, comment.Pos())
+		names := strings.TrimPrefix(comment.Text, contractDirective)
+		if i := strings.Index(names, "//"); i >= 0 {
+			// comment after names
+			names = names[:i]
+		}
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			src, ok := resolveContractName(pass.Pkg, imports, name)
+			if !ok {
+				bad = append(bad, name)
+				continue
+			}
+			c.Errors = append(c.Errors, src)
+		}
+		return c, bad
+	}
+	return nil, nil
+}
+
+// fileImports returns packages imported by file containing pos by names used in file (aliases or package names)
+func fileImports(pass *analysis.Pass, pos token.Pos) map[string]*types.Package {
+	imports := make(map[string]*types.Package)
+	for _, file := range pass.Files {
+		if pos < file.Pos() || pos > file.End() {
+			continue
+		}
+		for _, spec := range file.Imports {
+			var obj types.Object
+			if spec.Name != nil {
+				obj = pass.TypesInfo.Defs[spec.Name]
+			} else {
+				obj = pass.TypesInfo.Implicits[spec]
+			}
+			if pkgName, ok := obj.(*types.PkgName); ok {
+				imports[pkgName.Name()] = pkgName.Imported()
+			}
+		}
+	}
+	return imports
+}
+
+// resolveContractName resolves "Name", "*Name", "pkg.Name" or "*pkg.Name" to error source.
+// Type which methods of error are declared on pointer is resolved to pointer
+func resolveContractName(pkg *types.Package, imports map[string]*types.Package, name string) (ErrorSource, bool) {
+	pointer := strings.HasPrefix(name, "*")
+	name = strings.TrimPrefix(name, "*")
+	scope := pkg.Scope()
+	if i := strings.Index(name, "."); i >= 0 {
+		imp, ok := imports[name[:i]]
+		if !ok {
+			return ErrorSource{}, false
+		}
+		scope = imp.Scope()
+		name = name[i+1:]
+	}
+	switch obj := scope.Lookup(name).(type) {
+	case *types.TypeName:
+		t := obj.Type()
+		if pointer {
+			t = types.NewPointer(t)
+		}
+		if !isErrorType(t) {
+			if pointer || !isErrorType(types.NewPointer(t)) {
+				// not an error
+				return ErrorSource{}, false
+			}
+			t = types.NewPointer(t)
+		}
+		src := ErrorSource{Type: types.TypeString(t, nil)}
+		if obj.Pkg() != nil {
+			src.Pkg = obj.Pkg().Path()
+		}
+		return src, true
+	case *types.Var:
+		if pointer || obj.Pkg() == nil {
+			return ErrorSource{}, false
+		}
+		return ErrorSource{Sentinel: obj.Pkg().Path() + "." + obj.Name(), Pkg: obj.Pkg().Path()}, true
+	}
+	return ErrorSource{}, false
+}
+
+// checkContract reports errors function can return that are not declared in its contract
+// or in contracts of interface methods it implements, and declared errors function never returns.
+// Unknown sources are reported by other rules: with them declared errors are not checked
+func checkContract(pass *analysis.Pass, cfg *Config, res *Result, fn *ssa.Function, set []ErrorSource) {
+	for _, ic := range implementedContracts(res, fn) {
+		reportUndeclared(pass, cfg, res, fn, ic.c, set, "contract of "+ic.method.FullName())
+	}
+	decl, ok := fn.Syntax().(*ast.FuncDecl)
+	if !ok {
+		return
+	}
+	c, bad := parseContract(pass, decl.Doc)
+	if c == nil {
+		return
+	}
+	funcName := fn.RelString(nil)
+	reportBadNames(pass, cfg, res, c, bad, funcName)
+	if !reportUndeclared(pass, cfg, res, fn, c, set, "contract") {
+		return
+	}
+	for _, e := range c.Errors {
+		produced := false
+		for _, src := range set {
+			if (&contract{Errors: []ErrorSource{e}}).allows(src) {
+				produced = true
+				break
+			}
+		}
+		if !produced {
+			reportFinding(pass, cfg, res, Finding{Pos: c.Pos, Rule: RuleUnproducedError, Func: funcName, Origin: e.String(),
+				Message: fmt.Sprintf("declared error %s is never returned", e)})
+		}
+	}
+}
+
+func reportBadNames(pass *analysis.Pass, cfg *Config, res *Result, c *contract, bad []string, funcName string) {
+	for _, name := range bad {
+		reportFinding(pass, cfg, res, Finding{Pos: c.Pos, Rule: RuleUnproducedError, Func: funcName, Origin: name,
+			Message: fmt.Sprintf("unknown error in contract: %s", name)})
+	}
+}
+
+// reportUndeclared reports errors of set not allowed by contract, returns false if set has unknown sources
+func reportUndeclared(pass *analysis.Pass, cfg *Config, res *Result, fn *ssa.Function, c *contract, set []ErrorSource, what string) bool {
+	known := true
+	for _, src := range set {
+		if src.Unknown != "" {
+			known = false
+			continue
+		}
+		if c.allows(src) {
+			continue
+		}
+		message := fmt.Sprintf("error %s is not declared in %s", src, what)
+		if len(src.Via) > 0 {
+			message += " (via " + strings.Join(src.Via, " -> ") + ")"
+		}
+		reportFinding(pass, cfg, res, Finding{Pos: fn.Pos(), Rule: RuleUndeclaredError, Func: fn.RelString(nil), Origin: src.String(), Message: message})
+	}
+	return known
+}
+
+// ContractFact is error contract of method of exported interface
+type ContractFact struct {
+	Errors []ErrorSource
+}
+
+func (*ContractFact) AFact() {}
+
+func (f *ContractFact) String() string {
+	return (&ErrorsFact{Errors: f.Errors}).String()
+}
+
+// ifaceContract is contract of interface method
+type ifaceContract struct {
+	named  *types.Named
+	method *types.Func
+	c      *contract
+}
+
+// collectInterfaceContracts finds contracts of methods of interfaces declared in package
+// (contracts of exported interfaces are exported as facts) and of interfaces of imported packages
+func collectInterfaceContracts(pass *analysis.Pass, cfg *Config, res *Result) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			iface, ok := spec.Type.(*ast.InterfaceType)
+			if !ok {
+				return true
+			}
+			named, ok := pass.TypesInfo.Defs[spec.Name].Type().(*types.Named)
+			if !ok {
+				return true
+			}
+			for _, field := range iface.Methods.List {
+				if len(field.Names) != 1 {
+					// embedded interface
+					continue
+				}
+				method, ok := pass.TypesInfo.Defs[field.Names[0]].(*types.Func)
+				if !ok {
+					continue
+				}
+				c, bad := parseContract(pass, field.Doc)
+				if c == nil {
+					continue
+				}
+				reportBadNames(pass, cfg, res, c, bad, method.FullName())
+				res.contracts = append(res.contracts, ifaceContract{named: named, method: method, c: c})
+				if spec.Name.IsExported() && method.Exported() {
+					pass.ExportObjectFact(method, &ContractFact{Errors: c.Errors})
+				}
+			}
+			return true
+		})
+	}
+	for _, imp := range pass.Pkg.Imports() {
+		scope := imp.Scope()
+		for _, name := range scope.Names() {
+			named, ok := scope.Lookup(name).Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			iface, ok := named.Underlying().(*types.Interface)
+			if !ok {
+				continue
+			}
+			for i := 0; i < iface.NumExplicitMethods(); i++ {
+				method := iface.ExplicitMethod(i)
+				var fact ContractFact
+				if pass.ImportObjectFact(method, &fact) {
+					res.contracts = append(res.contracts, ifaceContract{named: named, method: method, c: &contract{Errors: fact.Errors}})
+				}
+			}
+		}
+	}
+}
+
+// contractOf returns contract of interface method or nil
+func contractOf(pass *analysis.Pass, res *Result, method *types.Func) *contract {
+	for _, ic := range res.contracts {
+		if ic.method == method {
+			return ic.c
+		}
+	}
+	if method.Pkg() == nil || method.Pkg() == pass.Pkg {
+		return nil
+	}
+	var fact ContractFact
+	if pass.ImportObjectFact(method, &fact) {
+		return &contract{Errors: fact.Errors}
+	}
+	return nil
+}
+
+// implementedContracts returns contracts of interface methods implemented by method fn
+func implementedContracts(res *Result, fn *ssa.Function) []ifaceContract {
+	recv := fn.Signature.Recv()
+	if recv == nil {
+		return nil
+	}
+	var found []ifaceContract
+	for _, ic := range res.contracts {
+		if ic.method.Name() != fn.Name() {
+			continue
+		}
+		if implements(recv.Type(), ic.named.Underlying().(*types.Interface)) {
+			found = append(found, ic)
+		}
+	}
+	return found
+}
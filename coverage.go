@@ -0,0 +1,47 @@
+package myerrorlint
+
+import "go/token"
+
+// Classes of return sites for coverage report
+const (
+	ClassAllowed      = "verified-allowed" // all returned errors are checked and allowed
+	ClassViolation    = "violation"        // some returned error is not allowed
+	ClassUnverifiable = "unverifiable"     // some returned error can not be checked (global, field, param, dynamic call)
+	ClassUnsupported  = "unsupported"      // linter does not support some case
+)
+
+// classPriority - site with several findings gets class with the biggest priority
+var classPriority = map[string]int{
+	ClassAllowed:      0,
+	ClassUnsupported:  1,
+	ClassUnverifiable: 2,
+	ClassViolation:    3,
+}
+
+// ReturnSite is a return of error from function classified by what linter found for it
+type ReturnSite struct {
+	Pos   token.Pos
+	Func  string
+	Class string
+}
+
+// ruleClass returns class of return site with finding of rule
+func ruleClass(rule string) string {
+	switch rule {
+	case RuleGlobal, RuleMapLookup, RuleStructField, RuleSliceElement, RuleParameter, RuleDynamicCall:
+		return ClassUnverifiable
+	case RuleUnsupported:
+		return ClassUnsupported
+	}
+	return ClassViolation
+}
+
+// add updates class of site with finding of rule (even if rule is disabled or not reported)
+func (s *ReturnSite) add(rule string) {
+	if s == nil {
+		return
+	}
+	if class := ruleClass(rule); classPriority[class] > classPriority[s.Class] {
+		s.Class = class
+	}
+}
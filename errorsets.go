@@ -0,0 +1,223 @@
+package myerrorlint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// ErrorSource is one kind of error function can return
+type ErrorSource struct {
+	Type     string   // concrete type of error like "*io/fs.PathError"
+	Sentinel string   // global error var like "io.EOF"
+	Unknown  string   // what can not be resolved: parameter, dynamic call, function without facts
+	Pkg      string   // package error originates in if known
+	Via      []string // functions error comes through from function to origin
+	Wrapped  bool     // error is wrapped by wrap func on the way
+}
+
+func (s ErrorSource) String() string {
+	switch {
+	case s.Type != "":
+		return s.Type
+	case s.Sentinel != "":
+		return s.Sentinel
+	}
+	return "unknown " + s.Unknown
+}
+
+type sourceKey struct {
+	Type, Sentinel, Unknown string
+}
+
+func (s ErrorSource) key() sourceKey {
+	return sourceKey{Type: s.Type, Sentinel: s.Sentinel, Unknown: s.Unknown}
+}
+
+// ErrorsFact is a set of errors exported function can return
+type ErrorsFact struct {
+	Errors []ErrorSource
+}
+
+func (*ErrorsFact) AFact() {}
+
+func (f *ErrorsFact) String() string {
+	items := make([]string, 0, len(f.Errors))
+	for _, s := range f.Errors {
+		items = append(items, s.String())
+	}
+	return strings.Join(items, ", ")
+}
+
+// FuncErrors is a set of errors function can return
+type FuncErrors struct {
+	Func     string
+	Pos      token.Pos
+	Exported bool
+	Errors   []ErrorSource
+}
+
+// leafSource is origin of returned error found by walker
+type leafSource struct {
+	src      ErrorSource
+	callee   *ssa.Function // error is returned by callee: resolved to its error set
+	optional bool          // callee of wrap call: its error set is ignored if unknown
+	layer    bool          // callee adds layer: only types of its error set are errors of caller
+	wrapped  bool
+}
+
+func (w *walker) addSource(src ErrorSource) {
+	src.Wrapped = w.wrapped > 0
+	w.sources = append(w.sources, leafSource{src: src})
+}
+
+func (w *walker) addCallSource(callee *ssa.Function, optional bool) {
+	w.sources = append(w.sources, leafSource{callee: callee, optional: optional, wrapped: w.wrapped > 0})
+}
+
+func (w *walker) addLayerSource(callee *ssa.Function) {
+	w.sources = append(w.sources, leafSource{callee: callee, layer: true, wrapped: w.wrapped > 0})
+}
+
+// errorSet is ErrorSource set with the shortest Via for every source
+type errorSet struct {
+	index   map[sourceKey]int
+	sources []ErrorSource
+}
+
+func (s *errorSet) add(src ErrorSource) {
+	if s.index == nil {
+		s.index = make(map[sourceKey]int)
+	}
+	key := src.key()
+	if i, ok := s.index[key]; ok {
+		if len(src.Via) < len(s.sources[i].Via) {
+			s.sources[i] = src
+		}
+		return
+	}
+	s.index[key] = len(s.sources)
+	s.sources = append(s.sources, src)
+}
+
+func (s *errorSet) sorted() []ErrorSource {
+	res := append([]ErrorSource(nil), s.sources...)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].String() < res[j].String()
+	})
+	return res
+}
+
+// resolveErrorSets computes error sets of functions of package:
+// calls of functions of the package are resolved from their sets, other calls by ErrorsFact.
+// Sets of recursive functions depend on each other so they are recomputed until nothing changes.
+// Sets of exported functions are exported as facts, sets are checked against contracts and layer rules
+func resolveErrorSets(pass *analysis.Pass, cfg *Config, funcs []*ssa.Function, res *Result) {
+	all := make([]*ssa.Function, 0, len(res.sources))
+	for fn := range res.sources {
+		all = append(all, fn)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].String() < all[j].String()
+	})
+	sets := make(map[*ssa.Function][]ErrorSource, len(all))
+	resolve := func(fn *ssa.Function) []ErrorSource {
+		var set errorSet
+		for _, ls := range res.sources[fn] {
+			if ls.callee == nil {
+				set.add(ls.src)
+				continue
+			}
+			var calleeSet []ErrorSource
+			if _, ok := res.sources[ls.callee]; ok {
+				calleeSet = sets[ls.callee]
+			} else if fact := calleeFact(pass, ls.callee); fact != nil {
+				calleeSet = fact.Errors
+			} else if !ls.optional {
+				set.add(ErrorSource{Unknown: ls.callee.String(), Pkg: funcPkgPath(ls.callee), Wrapped: ls.wrapped})
+			}
+			for _, src := range calleeSet {
+				if ls.layer && src.Type == "" {
+					// cause or unknown errors of wrap function
+					continue
+				}
+				src.Via = append([]string{ls.callee.String()}, src.Via...)
+				src.Wrapped = src.Wrapped || ls.wrapped
+				set.add(src)
+			}
+		}
+		return set.sorted()
+	}
+	// sets only grow and Via only get shorter so iteration ends
+	for changed := true; changed; {
+		changed = false
+		for _, fn := range all {
+			set := resolve(fn)
+			if !sameSources(set, sets[fn]) {
+				sets[fn] = set
+				changed = true
+			}
+		}
+	}
+	for _, fn := range funcs {
+		if _, ok := res.sources[fn]; !ok {
+			// does not return error
+			continue
+		}
+		set := sets[fn]
+		checkContract(pass, cfg, res, fn, set)
+		checkLayers(pass, cfg, res, fn, set)
+		exported := isExportedFunc(fn)
+		res.Funcs = append(res.Funcs, FuncErrors{Func: fn.RelString(nil), Pos: fn.Pos(), Exported: exported, Errors: set})
+		if exported && len(set) > 0 {
+			pass.ExportObjectFact(fn.Object(), &ErrorsFact{Errors: set})
+		}
+	}
+}
+
+func sameSources(a, b []ErrorSource) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].key() != b[i].key() || len(a[i].Via) != len(b[i].Via) || a[i].Wrapped != b[i].Wrapped {
+			return false
+		}
+	}
+	return true
+}
+
+func calleeFact(pass *analysis.Pass, callee *ssa.Function) *ErrorsFact {
+	obj, ok := callee.Object().(*types.Func)
+	if !ok || obj.Pkg() == nil || obj.Pkg() == pass.Pkg {
+		return nil
+	}
+	var fact ErrorsFact
+	if !pass.ImportObjectFact(obj, &fact) {
+		return nil
+	}
+	return &fact
+}
+
+// isExportedFunc checks if function or method of exported type is exported
+func isExportedFunc(fn *ssa.Function) bool {
+	obj, ok := fn.Object().(*types.Func)
+	if !ok || !obj.Exported() {
+		return false
+	}
+	recv := fn.Signature.Recv()
+	if recv == nil {
+		return true
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && ast.IsExported(named.Obj().Name())
+}
@@ -0,0 +1,113 @@
+package myerrorlint
+
+import (
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// InterfaceExemption allows methods of types implementing interface to return errors of its convention
+// (like io.EOF from Read) instead of AllowedTypes
+type InterfaceExemption struct {
+	Interface string   // interface with package path like "io.Reader" or "database/sql.Scanner"
+	Errors    []string // error types and vars like "io.EOF" or "*encoding/json.SyntaxError"
+}
+
+// ifaceExemption is InterfaceExemption with interface found in imports of package
+type ifaceExemption struct {
+	name   string
+	iface  *types.Interface
+	errors []string
+}
+
+// exemption is errors allowed for method by all exemptions of interfaces it implements
+type exemption struct {
+	ifaces []string
+	errors []string
+}
+
+func (e *exemption) allows(name string) bool {
+	for _, allowed := range e.errors {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveExemptions finds interfaces of exemptions in packages imported by package (directly or not).
+// Interfaces of packages not imported can not be implemented knowingly and are skipped
+func resolveExemptions(pass *analysis.Pass, cfg *Config, res *Result) {
+	if len(cfg.InterfaceExemptions) == 0 {
+		return
+	}
+	pkgs := make(map[string]*types.Package)
+	var visit func(pkg *types.Package)
+	visit = func(pkg *types.Package) {
+		if _, ok := pkgs[pkg.Path()]; ok {
+			return
+		}
+		pkgs[pkg.Path()] = pkg
+		for _, imp := range pkg.Imports() {
+			visit(imp)
+		}
+	}
+	visit(pass.Pkg)
+	for _, ex := range cfg.InterfaceExemptions {
+		i := strings.LastIndex(ex.Interface, ".")
+		if i < 0 {
+			continue
+		}
+		pkg, ok := pkgs[ex.Interface[:i]]
+		if !ok {
+			continue
+		}
+		obj, ok := pkg.Scope().Lookup(ex.Interface[i+1:]).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if iface, ok := obj.Type().Underlying().(*types.Interface); ok {
+			res.exemptions = append(res.exemptions, ifaceExemption{name: ex.Interface, iface: iface, errors: ex.Errors})
+		}
+	}
+}
+
+// exemptionOf returns errors allowed for method implementing interfaces of exemptions, nil if there are none
+func exemptionOf(res *Result, fn *ssa.Function) *exemption {
+	recv := fn.Signature.Recv()
+	if recv == nil {
+		return nil
+	}
+	var found *exemption
+	for _, ex := range res.exemptions {
+		if !hasMethod(ex.iface, fn.Name()) || !implements(recv.Type(), ex.iface) {
+			continue
+		}
+		if found == nil {
+			found = &exemption{}
+		}
+		found.ifaces = append(found.ifaces, ex.name)
+		found.errors = append(found.errors, ex.errors...)
+	}
+	return found
+}
+
+func hasMethod(iface *types.Interface, name string) bool {
+	for i := 0; i < iface.NumMethods(); i++ {
+		if iface.Method(i).Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// implements checks if type or pointer to it implements interface
+func implements(t types.Type, iface *types.Interface) bool {
+	if types.Implements(t, iface) {
+		return true
+	}
+	_, ok := t.(*types.Pointer)
+	return !ok && types.Implements(types.NewPointer(t), iface)
+}
@@ -0,0 +1,208 @@
+package myerrorlint
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// hasErrorPositions checks if error can be stored in value of type:
+// error field of struct of our packages, element of slice, array or map (recursively)
+func hasErrorPositions(t types.Type, pkg *types.Package, cfg *Config, seen map[types.Type]bool) bool {
+	if types.IsInterface(t) {
+		return isErrorType(t)
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t := t.(type) {
+	case *types.Named:
+		if t.Obj().Pkg() == nil || (t.Obj().Pkg() != pkg && !isOurPkg(t.Obj().Pkg().Path(), cfg)) {
+			// fields of not our types are not checked
+			return false
+		}
+		return hasErrorPositions(t.Underlying(), pkg, cfg, seen)
+	case *types.Pointer:
+		return hasErrorPositions(t.Elem(), pkg, cfg, seen)
+	case *types.Slice:
+		return hasErrorPositions(t.Elem(), pkg, cfg, seen)
+	case *types.Array:
+		return hasErrorPositions(t.Elem(), pkg, cfg, seen)
+	case *types.Map:
+		return hasErrorPositions(t.Elem(), pkg, cfg, seen)
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if hasErrorPositions(t.Field(i).Type(), pkg, cfg, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldsBySignature returns indices of return variables (not errors) that can hold errors
+func fieldsBySignature(sign *types.Signature, pkg *types.Package, cfg *Config) []int {
+	results := sign.Results()
+	var res []int
+	for i := 0; i < results.Len(); i++ {
+		t := results.At(i).Type()
+		if !isErrorType(t) && hasErrorPositions(t, pkg, cfg, make(map[types.Type]bool)) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+func isErrorInterface(t types.Type) bool {
+	return types.IsInterface(t) && isErrorType(t)
+}
+
+// checkFields checks errors stored to error fields and elements of returned value like returned ones.
+// They are not added to error set of function
+func (w *walker) checkFields(ret *ssa.Return, v ssa.Value) {
+	sources := w.sources
+	w.seen = make(map[ssa.Value]bool)
+	w.followValue(ret, v, path{{ret.Pos(), "returned"}}, make(map[ssa.Value]bool))
+	w.sources = sources
+}
+
+// followValue finds where value holding errors is built
+func (w *walker) followValue(from graphNode, v ssa.Value, p path, seen map[ssa.Value]bool) {
+	if seen[v] {
+		return
+	}
+	seen[v] = true
+	w.graph.addEdge(from, v, "fields")
+	w.current = v
+	switch v := v.(type) {
+	case *ssa.Const:
+		// nil slice or map, zero struct
+	case *ssa.Alloc, *ssa.MakeSlice, *ssa.MakeMap:
+		w.followAddr(v, p, seen)
+	case *ssa.UnOp:
+		if v.Op != token.MUL {
+			w.unknownf(retPos(v, w.fn.Pos()), p, "unsupported case for value with error fields: %s", valueKind(v))
+			return
+		}
+		if !w.followAddr(v.X, p, seen) && !isLocalAddr(v.X) {
+			// value is stored outside of function
+			w.reportLoaded(v, p)
+		}
+	case *ssa.Parameter:
+		w.reportf(RuleParameter, retPos(v, w.fn.Pos()), p, v.Name(), "cant check error type for %v", v)
+	case *ssa.Slice:
+		w.followAddr(v, p, seen)
+		w.followValue(v, v.X, p, seen)
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			w.followValue(v, edge, p, seen)
+		}
+	case *ssa.Call:
+		common := v.Common()
+		if blt, ok := common.Value.(*ssa.Builtin); ok && blt.Name() == "append" {
+			for _, arg := range common.Args {
+				w.followValue(v, arg, p, seen)
+			}
+			return
+		}
+		if function := common.StaticCallee(); function != nil && isOurPkg(funcPkgPath(function), w.cfg) {
+			// fields of values returned by our functions are checked in them
+			return
+		}
+		w.unknownf(retPos(v, w.fn.Pos()), p, "unsupported case for value with error fields: %s", valueKind(v))
+	default:
+		w.unknownf(retPos(v, w.fn.Pos()), p, "unsupported case for value with error fields: %s", valueKind(v))
+	}
+}
+
+// isLocalAddr checks if address is in value allocated by function: all stores to it are in function
+func isLocalAddr(addr ssa.Value) bool {
+	for {
+		switch a := addr.(type) {
+		case *ssa.FieldAddr:
+			addr = a.X
+		case *ssa.IndexAddr:
+			addr = a.X
+		case *ssa.Alloc, *ssa.MakeSlice, *ssa.MakeMap:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// reportLoaded reports value with error fields loaded from address not allocated by function
+func (w *walker) reportLoaded(v *ssa.UnOp, p path) {
+	pos := retPos(v, w.fn.Pos())
+	switch addr := v.X.(type) {
+	case *ssa.Global:
+		w.reportf(RuleGlobal, pos, p, addr.RelString(nil), "cant check error type for global: %s", addr.Name())
+	case *ssa.FieldAddr:
+		w.reportf(RuleStructField, pos, p, "", "cant check error type for struct field")
+	case *ssa.IndexAddr:
+		w.reportf(RuleSliceElement, pos, p, "", "cant check error type for slice element")
+	default:
+		w.unknownf(pos, p, "unsupported case for value with error fields: %s", valueKind(v))
+	}
+}
+
+// followAddr checks values stored by address (or to slice or map) to error positions.
+// Returns false if no stores are found
+func (w *walker) followAddr(addr ssa.Value, p path, seen map[ssa.Value]bool) bool {
+	if addr.Referrers() == nil {
+		return false
+	}
+	stored := false
+	for _, instr := range *addr.Referrers() {
+		switch instr := instr.(type) {
+		case *ssa.Store:
+			if instr.Addr != addr {
+				continue
+			}
+			stored = true
+			pos := retPos(instr, w.fn.Pos())
+			storePath := p
+			switch addr.(type) {
+			case *ssa.FieldAddr:
+				// step of field is added already
+			case *ssa.IndexAddr:
+				storePath = p.with(pos, "stored to element")
+			default:
+				storePath = p.with(pos, "stored")
+			}
+			if isErrorInterface(instr.Val.Type()) {
+				w.follow(instr, "field-store", instr.Val, pos, storePath)
+				continue
+			}
+			w.followValue(instr, instr.Val, storePath, seen)
+		case *ssa.FieldAddr:
+			if instr.X != addr {
+				continue
+			}
+			field := instr.X.Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Struct).Field(instr.Field)
+			if hasErrorPositions(field.Type(), w.pass.Pkg, w.cfg, make(map[types.Type]bool)) {
+				pos := retPos(instr, w.fn.Pos())
+				stored = w.followAddr(instr, p.with(pos, "stored to field "+field.Name()), seen) || stored
+			}
+		case *ssa.IndexAddr:
+			if instr.X != addr {
+				continue
+			}
+			stored = w.followAddr(instr, p, seen) || stored
+		case *ssa.MapUpdate:
+			if instr.Map != addr {
+				continue
+			}
+			stored = true
+			pos := retPos(instr, w.fn.Pos())
+			if isErrorInterface(instr.Value.Type()) {
+				w.follow(instr, "map-store", instr.Value, pos, p.with(pos, "stored to map"))
+				continue
+			}
+			w.followValue(instr, instr.Value, p.with(pos, "stored to map"), seen)
+		}
+	}
+	return stored
+}
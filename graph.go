@@ -0,0 +1,152 @@
+package myerrorlint
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+const VerdictAllowed = "allowed"
+
+// Graph of values walked from returns of a function to origins of returned errors
+type Graph struct {
+	Func  string
+	Nodes []GraphNode
+	Edges []GraphEdge
+
+	ids map[graphNode]int
+}
+
+// GraphNode is SSA value or return instruction
+type GraphNode struct {
+	ID    int
+	Label string // SSA of node
+	Kind  string // SSA node type like "*ssa.Phi"
+	Pos   token.Pos
+	// for leaves: VerdictAllowed or ID of rule of finding
+	Verdict string
+	Message string // finding or why value is allowed
+	Origin  string // origin of finding
+	Setting string // setting of Config the leaf is allowed by (or would be allowed by if it is not the usual one)
+}
+
+// GraphEdge from value to value it takes error from
+type GraphEdge struct {
+	From, To int
+	Kind     string // "return", "phi", "store", "wrapped", ...
+}
+
+// graphNode is ssa.Value or ssa.Instruction
+type graphNode interface {
+	String() string
+	Pos() token.Pos
+}
+
+func newGraph(fn *ssa.Function) *Graph {
+	return &Graph{Func: fn.RelString(nil), ids: make(map[graphNode]int)}
+}
+
+func (g *Graph) node(n graphNode) int {
+	if id, ok := g.ids[n]; ok {
+		return id
+	}
+	id := len(g.Nodes)
+	label := n.String()
+	if v, ok := n.(ssa.Value); ok && v.Name() != label {
+		label = v.Name() + " = " + label
+	}
+	g.Nodes = append(g.Nodes, GraphNode{ID: id, Label: label, Kind: fmt.Sprintf("%T", n), Pos: n.Pos()})
+	g.ids[n] = id
+	return id
+}
+
+func (g *Graph) addEdge(from, to graphNode, kind string) {
+	if g == nil {
+		return
+	}
+	fromID := g.node(from)
+	toID := g.node(to)
+	for _, e := range g.Edges {
+		if e.From == fromID && e.To == toID && e.Kind == kind {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, GraphEdge{From: fromID, To: toID, Kind: kind})
+}
+
+func (g *Graph) setVerdict(n graphNode, verdict, message string) {
+	if g == nil || n == nil {
+		return
+	}
+	id := g.node(n)
+	g.Nodes[id].Verdict = verdict
+	g.Nodes[id].Message = message
+}
+
+func (g *Graph) setOrigin(n graphNode, origin, setting string) {
+	if g == nil || n == nil {
+		return
+	}
+	id := g.node(n)
+	g.Nodes[id].Origin = origin
+	g.Nodes[id].Setting = setting
+}
+
+// graphRequested checks if fn is selected by cfg.GraphFuncs
+// by qualified name or by position: file.go:line inside of fn but not inside of its closures
+func graphRequested(fset *token.FileSet, fn *ssa.Function, cfg *Config) bool {
+	for _, sel := range cfg.GraphFuncs {
+		if fn.RelString(nil) == sel {
+			return true
+		}
+		if file, line, ok := splitFileLine(sel); ok && funcContains(fset, fn, file, line) {
+			inClosure := false
+			for _, anon := range fn.AnonFuncs {
+				if funcContains(fset, anon, file, line) {
+					inClosure = true
+				}
+			}
+			if !inClosure {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitFileLine splits "path/file.go:12"
+func splitFileLine(s string) (file string, line int, ok bool) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 || !strings.HasSuffix(s[:i], ".go") {
+		return "", 0, false
+	}
+	line, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return s[:i], line, true
+}
+
+// funcContains checks if source of fn contains line of file (file may be relative or base name)
+func funcContains(fset *token.FileSet, fn *ssa.Function, file string, line int) bool {
+	syntax := fn.Syntax()
+	if syntax == nil {
+		return false
+	}
+	start := fset.Position(syntax.Pos())
+	end := fset.Position(syntax.End())
+	if !sameFile(start.Filename, file) {
+		return false
+	}
+	return start.Line <= line && line <= end.Line
+}
+
+func sameFile(name, file string) bool {
+	name = filepath.ToSlash(name)
+	file = filepath.ToSlash(filepath.Clean(file))
+	return name == file || strings.HasSuffix(name, "/"+file)
+}
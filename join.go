@@ -0,0 +1,72 @@
+package myerrorlint
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// JoinMode values: when error joined from several errors is allowed
+const (
+	JoinAll = "all" // every joined error is allowed (default)
+	JoinAny = "any" // at least one joined error is allowed
+)
+
+// isJoinCall checks if call joins errors (like errors.Join) and returns joined errors:
+// error args and elements of variadic ...error arg
+func isJoinCall(call *ssa.CallCommon, cfg *Config) (isJoin bool, joined []ssa.Value) {
+	function := call.StaticCallee()
+	fullName := funcPkgPath(function) + "." + function.Name()
+	for _, joinFunc := range cfg.JoinFuncs {
+		if joinFunc != fullName {
+			continue
+		}
+		for _, arg := range call.Args {
+			if isErrorType(arg.Type()) {
+				joined = append(joined, arg)
+				continue
+			}
+			// errors passed to ...error
+			joined = append(joined, sliceElements(arg)...)
+		}
+		return true, joined
+	}
+	return false, nil
+}
+
+// checkJoined checks errors joined by call.
+// In JoinAny mode findings of joined errors are reported only if none of them is allowed.
+// Joined error is allowed if checking it found nothing, including findings of values checked before
+func (w *walker) checkJoined(function *ssa.Function, joined []ssa.Value, pos token.Pos, p path) {
+	p = p.with(pos, "joined by "+function.String())
+	if w.cfg.JoinMode != JoinAny {
+		for _, v := range joined {
+			w.follow(w.current, "joined", v, pos, p)
+		}
+		return
+	}
+	outer, findings := w.deferred, w.findings
+	reports := make([][]func(), 0, len(joined))
+	anyAllowed := false
+	for _, v := range joined {
+		var deferred []func()
+		w.deferred = &deferred
+		before := w.findings
+		w.follow(w.current, "joined", v, pos, p)
+		if w.findings == before {
+			anyAllowed = true
+		}
+		reports = append(reports, deferred)
+	}
+	w.deferred = outer
+	if anyAllowed {
+		w.findings = findings
+		w.allowed("JoinMode", "one of errors joined by %s is allowed", function.String())
+		return
+	}
+	for _, r := range reports {
+		for _, report := range r {
+			report()
+		}
+	}
+}
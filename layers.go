@@ -0,0 +1,50 @@
+package myerrorlint
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// LayerRule forbids errors originating in packages of one layer to be returned from packages of other layer.
+// Packages are paths or dirs of packages ending with "/" like in OurPackages
+type LayerRule struct {
+	Origin     string   // name of layer errors come from like "database"
+	OriginPkgs []string // like "database/sql", "github.com/jackc/"
+	Target     string   // name of layer errors must not be returned from like "service"
+	TargetPkgs []string // like "example.com/svc/internal/service/"
+}
+
+// checkLayers reports errors function can return that originate in layer forbidden for package of function.
+// Origin is package of error type or var, or of function without facts error is returned from
+func checkLayers(pass *analysis.Pass, cfg *Config, res *Result, fn *ssa.Function, set []ErrorSource) {
+	pkgPath := pass.Pkg.Path()
+	for _, rule := range cfg.Layers {
+		if !inPackages(pkgPath, rule.TargetPkgs) {
+			continue
+		}
+		for _, src := range set {
+			if src.Pkg == "" || !inPackages(src.Pkg, rule.OriginPkgs) {
+				continue
+			}
+			origin := src.String()
+			if src.Unknown != "" {
+				origin = src.Unknown
+			}
+			message := fmt.Sprintf("error of layer %s (%s) must not be returned from layer %s", rule.Origin, origin, rule.Target)
+			var notes []string
+			if src.Wrapped {
+				notes = append(notes, "wrapped")
+			}
+			if len(src.Via) > 0 {
+				notes = append(notes, "via "+strings.Join(src.Via, " -> "))
+			}
+			if len(notes) > 0 {
+				message += " (" + strings.Join(notes, ", ") + ")"
+			}
+			reportFinding(pass, cfg, res, Finding{Pos: fn.Pos(), Rule: RuleLayer, Func: fn.RelString(nil), Origin: src.String(), Message: message})
+		}
+	}
+}
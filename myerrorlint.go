@@ -6,6 +6,7 @@ import (
 	"go/constant"
 	"go/token"
 	"go/types"
+	"reflect"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -14,13 +15,12 @@ import (
 )
 
 const Doc = `123 check for errors of wrong type returned from our functions (allowed type defined in cfg)
+Error fields and elements of returned values of our types are checked like returned errors.
 Unknown cases:
-	- Error from map, struct, slice - whould have to check all actions on that object,
-	also we dont check fields of objects in return values so we would not be able to assume
-	that our functions return correct objects. Use objects with allowed types instead of objects with error interface`
+	- Error from map, struct, slice - whould have to check all actions on that object.
+	Use objects with allowed types instead of objects with error interface`
 const Name = "myerrorlinttt"
 
-// TODO: if some of func can return external errors (for example Unwrap of our error) they can be ignorred but their return values should not be returned by other functions
 // TODO: check if func is a wrap function by its comments - need to somehow get function declaration tags for that
 
 type Config struct {
@@ -29,6 +29,36 @@ type Config struct {
 	ReportUnknown             bool     // report error if unknown case (error from map and such)
 	AllowErrorfWrap           bool     // check for fmt.Errorf wrapped error
 	WrapFuncWithFirstArgError []string // Wrap functions that take error as first param (like github.com/pkg/errors.Wrap)
+	Rules                     map[string]RuleConfig // per rule overrides by rule ID (see Rules)
+	GraphFuncs                []string              // functions to put graph of checked values to Result for (qualified name or file.go:line)
+	InterfaceExemptions       []InterfaceExemption  // errors allowed instead of AllowedTypes for methods implementing interfaces
+	Layers                    []LayerRule           // errors of packages that must not be returned from other packages
+	Sinks                     []Sink                // functions errors passed to are checked like returned ones
+	JoinFuncs                 []string              // functions joining errors like "errors.Join" or "go.uber.org/multierr.Append"
+	JoinMode                  string                // JoinAll (default) or JoinAny: joined error is allowed if all or any of joined errors is allowed
+	WrapFuncs                 []WrapFunc            // wrap functions adding allowed layer or passing chain through
+	ChainMode                 string                // ChainTop or ChainAs, by default cause of fmt.Errorf (with AllowErrorfWrap) and wrap functions is checked
+	BoundaryOnly              bool                  // only exported API is checked: findings of other functions are reported when their errors reach it
+}
+
+// Validate checks values of config that can not be checked by JSON decoding
+func (cfg *Config) Validate() error {
+	for _, wrapFunc := range cfg.WrapFuncs {
+		if wrapFunc.Arg < 0 {
+			return fmt.Errorf("bad Arg of wrap function %s: %d", wrapFunc.Func, wrapFunc.Arg)
+		}
+	}
+	switch cfg.JoinMode {
+	case "", JoinAll, JoinAny:
+	default:
+		return fmt.Errorf("bad JoinMode: %q", cfg.JoinMode)
+	}
+	switch cfg.ChainMode {
+	case "", ChainTop, ChainAs:
+	default:
+		return fmt.Errorf("bad ChainMode: %q", cfg.ChainMode)
+	}
+	return nil
 }
 
 func NewAnalyzerWithoutRun() *analysis.Analyzer {
@@ -94,6 +124,40 @@ func (s BoolValue) Inflate(dest bool) bool {
 	return dest
 }
 
+type RulesValue struct {
+	Value   map[string]RuleConfig
+	Defined bool
+}
+
+func (s *RulesValue) String() string {
+	items := make([]string, 0, len(s.Value))
+	for id, ruleCfg := range s.Value {
+		if ruleCfg.Disabled {
+			items = append(items, id+":"+string(SeverityOff))
+		} else {
+			items = append(items, id+":"+string(ruleCfg.Severity))
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+func (s *RulesValue) Set(src string) error {
+	rules, err := ParseRules(strings.Split(src, ","))
+	if err != nil {
+		return err
+	}
+	s.Value = rules
+	s.Defined = true
+	return nil
+}
+
+func (s RulesValue) Inflate(dest map[string]RuleConfig) map[string]RuleConfig {
+	if s.Defined {
+		return s.Value
+	}
+	return dest
+}
+
 var flagSet flag.FlagSet
 type Config2 struct {
 	AllowedTypes              StringSliceValue // if no type then only check that we return errors from our pkgs
@@ -101,6 +165,7 @@ type Config2 struct {
 	ReportUnknown             BoolValue     // report error if unknown case (error from map and such)
 	AllowErrorfWrap           BoolValue     // check for fmt.Errorf wrapped error
 	WrapFuncWithFirstArgError StringSliceValue // Wrap functions that take error as first param (like github.com/pkg/errors.Wrap)
+	Rules                     RulesValue       // per rule overrides like "global:off,unsupported:error"
 }
 
 func (cfg Config2) Export(dest *Config) {
@@ -109,14 +174,20 @@ func (cfg Config2) Export(dest *Config) {
 	dest.ReportUnknown = cfg.ReportUnknown.Inflate(dest.ReportUnknown)
 	dest.AllowErrorfWrap = cfg.AllowErrorfWrap.Inflate(dest.AllowErrorfWrap)
 	dest.WrapFuncWithFirstArgError = cfg.WrapFuncWithFirstArgError.Inflate(dest.WrapFuncWithFirstArgError)
+	dest.Rules = cfg.Rules.Inflate(dest.Rules)
 }
 var config Config2
 func setFlagset() {
+	if flagSet.Lookup("our-pkgs") != nil {
+		// already set by previous NewAnalyzer call
+		return
+	}
 	flagSet.Var(&config.AllowErrorfWrap, "allow-types", "")
 	flagSet.Var(&config.OurPackages, "our-pkgs", "")
 	flagSet.Var(&config.ReportUnknown, "report-unknown", "")
 	flagSet.Var(&config.AllowErrorfWrap, "allow-errorf-wrap", "")
 	flagSet.Var(&config.WrapFuncWithFirstArgError, "wrap-funcs", "")
+	flagSet.Var(&config.Rules, "rules", "per rule severity: id:error|warning|off,...")
 }
 
 //type ExtraAnalyser struct {
@@ -131,29 +202,39 @@ func setFlagset() {
 
 func NewAnalyzer(cfg Config) *analysis.Analyzer {
 	setFlagset()
-	fmt.Println("Flagset inflated")
 	return &analysis.Analyzer{
 			Name:     Name,
 			Doc:      Doc,
 			Requires: []*analysis.Analyzer{buildssa.Analyzer},
 			Run: NewRun(cfg),
 			Flags: flagSet,
+			ResultType: reflect.TypeOf(new(Result)),
+			FactTypes: []analysis.Fact{new(ErrorsFact), new(ContractFact)},
 		}
 }
 
 //will use cfg later
 func NewRun(cfg Config) func(pass *analysis.Pass) (interface{}, error) {
-	fmt.Println("Start flagset")
-	fmt.Println(flagSet.Name())
-	fmt.Println(flagSet.Args())
 	config.Export(&cfg)
-	fmt.Println(cfg.AllowedTypes, cfg.OurPackages, cfg.AllowErrorfWrap, cfg.ReportUnknown, cfg.WrapFuncWithFirstArgError)
 	return func(pass *analysis.Pass) (interface{}, error) {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
 		ssainput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+		res := &Result{sources: make(map[*ssa.Function][]leafSource), summaries: make(map[*ssa.Function][]Finding)}
+		collectInterfaceContracts(pass, &cfg, res)
+		resolveExemptions(pass, &cfg, res)
+		collectFactories(ssainput.SrcFuncs, res)
+		checkAllowedTypes(pass, &cfg, res, ssainput.Pkg.Prog)
 		for _, fn := range ssainput.SrcFuncs {
-			runFunc(pass, fn, &cfg)
+			runFunc(pass, fn, &cfg, res)
+			checkCauses(pass, &cfg, res, fn)
 		}
-		return nil, nil
+		if cfg.BoundaryOnly {
+			reportBoundaries(pass, &cfg, res, ssainput.SrcFuncs)
+		}
+		resolveErrorSets(pass, &cfg, ssainput.SrcFuncs, res)
+		return res, nil
 	}
 }
 
@@ -184,17 +265,18 @@ func errorsBySignature(sign *types.Signature) []int {
 	return res
 }
 
-func reportf(pass *analysis.Pass, pos token.Pos, format string, args ...interface{}) {
-	pass.Report(analysis.Diagnostic{
-		Pos:     pos,
-		Message: fmt.Sprintf(format, args...),
-	})
+func isOurPkg(pkgName string, cfg *Config) bool {
+	return inPackages(pkgName, cfg.OurPackages)
 }
 
-func isOurPkg(pkgName string, cfg *Config) bool {
-	for _, ourPkgStr := range cfg.OurPackages {
-		strLen := len(ourPkgStr)
-		if ourPkgStr[strLen-1:strLen] == "/" {
+// inPackages checks if package is in list of packages and dirs of packages (ending with "/").
+// Empty entries of list are skipped
+func inPackages(pkgName string, pkgs []string) bool {
+	for _, ourPkgStr := range pkgs {
+		if ourPkgStr == "" {
+			continue
+		}
+		if strings.HasSuffix(ourPkgStr, "/") {
 			// dir of pkgs
 			if strings.HasPrefix(pkgName, ourPkgStr) {
 				return true
@@ -214,33 +296,69 @@ func isAllowedErrorType(t types.Type, cfg *Config) bool {
 	return false
 }
 
+// isAllowedTypeName checks type from contract: type without pointer may be allowed as pointer
+func isAllowedTypeName(name string, cfg *Config) bool {
+	for _, allowedType := range cfg.AllowedTypes {
+		if name == allowedType || "*"+name == allowedType {
+			return true
+		}
+	}
+	return false
+}
+
+// typePkgPath returns path of package of named type or pointer to it
+func typePkgPath(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		return named.Obj().Pkg().Path()
+	}
+	return ""
+}
+
+// valueKind describes value in messages by kind of instruction and type like "Field error".
+// Values are not printed with %#v: pointers in it change every run
+func valueKind(v ssa.Value) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", v), "*ssa.") + " " + v.Type().String()
+}
+
+// funcPkgPath returns path of package of function
+// synthetic wrappers (of promoted or pointer receiver methods, bound method values, method expression thunks) have no Pkg
+func funcPkgPath(fn *ssa.Function) string {
+	if fn.Pkg != nil {
+		return fn.Pkg.Pkg.Path()
+	}
+	if obj := fn.Object(); obj != nil && obj.Pkg() != nil {
+		return obj.Pkg().Path()
+	}
+	return ""
+}
+
+// isWrapCall returns wrapped error if call is one of wrap functions
 func isWrapCall(call *ssa.CallCommon, cfg *Config) (isWrap bool, v ssa.Value) {
 	function := call.StaticCallee()
 	args := call.Args
-	if cfg.AllowErrorfWrap && function.Name() == "Errorf" && function.Pkg.Pkg.Path() == "fmt" {
+	if (cfg.AllowErrorfWrap || cfg.ChainMode == ChainAs) && isErrorf(function) {
 		// check if Errorf wraps error
 		if len(args) != 2 {
 			return false, nil
 		}
-		if fmtSlice, ok := args[1].(*ssa.Slice); ok {
-			// has IndexAddr for every arg passed to ...interface{}
-			for _, fmtPointer := range *fmtSlice.X.Referrers() {
-				if idxAddr, ok := fmtPointer.(*ssa.IndexAddr); ok {
-					// has command that stores interface to idxAddr
-					if storeInstr, ok := (*idxAddr.Referrers())[0].(*ssa.Store); ok {
-						if makeInterface, ok := storeInstr.Val.(*ssa.MakeInterface); ok {
-							// finaly check arg is error
-							if isErrorType(makeInterface.X.Type()) {
-								return true, makeInterface.X
-							}
-						}
-					}
-				}
+		// error passed to %v does not wrap it, if format is unknown any error may be wrapped
+		verbs, known := formatVerbs(args[0])
+		for i, val := range formatArgs(args[1]) {
+			err := errorOfArg(val)
+			if err == nil || (known && (i >= len(verbs) || verbs[i].verb != 'w')) {
+				continue
 			}
+			return true, err
 		}
 	}
+	if wrapFunc, ok := findWrapFunc(function, cfg); ok && wrapFunc.Arg < len(args) {
+		return true, args[wrapFunc.Arg]
+	}
 	for _, allowedFunc := range cfg.WrapFuncWithFirstArgError {
-		fullName := function.Pkg.Pkg.Path() + "." + function.Name()
+		fullName := funcPkgPath(function) + "." + function.Name()
 		if allowedFunc == fullName {
 			// wraps first param
 			if len(args) > 1 {
@@ -252,6 +370,27 @@ func isWrapCall(call *ssa.CallCommon, cfg *Config) (isWrap bool, v ssa.Value) {
 	return false, nil
 }
 
+// sliceElements returns values stored to slice of variadic args
+func sliceElements(v ssa.Value) []ssa.Value {
+	slice, ok := v.(*ssa.Slice)
+	if !ok {
+		return nil
+	}
+	var values []ssa.Value
+	// has IndexAddr for every arg passed to ...
+	for _, instr := range *slice.X.Referrers() {
+		if idxAddr, ok := instr.(*ssa.IndexAddr); ok {
+			// has command that stores arg to idxAddr
+			for _, ref := range *idxAddr.Referrers() {
+				if storeInstr, ok := ref.(*ssa.Store); ok {
+					values = append(values, storeInstr.Val)
+				}
+			}
+		}
+	}
+	return values
+}
+
 func retPos(v interface{ Pos() token.Pos }, defaultPos token.Pos) token.Pos {
 	if v.Pos() == token.NoPos {
 		return defaultPos
@@ -259,53 +398,221 @@ func retPos(v interface{ Pos() token.Pos }, defaultPos token.Pos) token.Pos {
 	return v.Pos()
 }
 
-func checkCallInstruction(pass *analysis.Pass, v ssa.CallInstruction, cfg *Config, defaultPos token.Pos, seen map[ssa.Value]bool) {
+// walker checks error values returned from one function
+type walker struct {
+	pass *analysis.Pass
+	cfg  *Config
+	fn   *ssa.Function
+	res  *Result
+	seen map[ssa.Value]bool // values already checked for current return: true if value has findings
+	site *ReturnSite        // current return
+
+	graph   *Graph    // graph of walked values, nil if not requested for the function
+	current graphNode // value being checked
+
+	sources []leafSource // origins of errors function can return
+	wrapped int          // >0 if checking error wrapped by wrap func
+
+	exemption *exemption // errors allowed by InterfaceExemptions for method, nil if it implements none
+	deferred  *[]func()  // if not nil reports are deferred here until joined errors are checked
+	findings  int        // count of findings (reported, deferred or unknown) of checked values
+	summarize bool       // findings at returns are kept in summary of function not on boundary (BoundaryOnly)
+
+	reported   map[reportKey]bool // findings at returns of function
+	afterPanic bool               // checking return after recovered panic: findings of other returns are not reported again
+}
+
+type reportKey struct {
+	pos          token.Pos
+	rule, origin string
+}
+
+func (w *walker) reportf(rule string, pos token.Pos, p path, origin string, format string, args ...interface{}) {
+	w.reportSettingf(rule, "", pos, p, origin, format, args...)
+}
+
+// reportSettingf reports finding recording setting of Config that would allow value in graph
+func (w *walker) reportSettingf(rule, setting string, pos token.Pos, p path, origin string, format string, args ...interface{}) {
+	w.findings++
+	if w.deferred != nil {
+		current := w.current
+		*w.deferred = append(*w.deferred, func() {
+			saved := w.current
+			w.current = current
+			w.reportSettingf(rule, setting, pos, p, origin, format, args...)
+			w.current = saved
+		})
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+	if factories := w.res.factories[w.fn]; len(factories) > 0 {
+		message += fmt.Sprintf(" (in closure returned by %s)", strings.Join(factories, ", "))
+	}
+	w.site.add(rule)
+	if ruleClass(rule) != ClassViolation && rule != RuleGlobal && rule != RuleDynamicCall {
+		// origin of error is unknown
+		w.addSource(ErrorSource{Unknown: rule})
+	}
+	w.graph.setVerdict(w.current, rule, message)
+	w.graph.setOrigin(w.current, origin, setting)
+	if w.site != nil {
+		key := reportKey{pos: pos, rule: rule, origin: origin}
+		if w.afterPanic && w.reported[key] {
+			// same value is returned by other return
+			return
+		}
+		w.reported[key] = true
+	}
+	f := Finding{
+		Pos:     pos,
+		Rule:    rule,
+		Message: message,
+		Func:    w.fn.RelString(nil),
+		Origin:  origin,
+		Related: p.related(),
+	}
+	if w.summarize && w.site != nil {
+		// reported if error reaches exported function
+		w.res.summaries[w.fn] = append(w.res.summaries[w.fn], f)
+		return
+	}
+	reportFinding(w.pass, w.cfg, w.res, f)
+}
+
+// allowed records that current value is allowed and by which setting of config
+func (w *walker) allowed(setting string, format string, args ...interface{}) {
+	w.graph.setVerdict(w.current, VerdictAllowed, fmt.Sprintf(format, args...))
+	w.graph.setOrigin(w.current, "", setting)
+}
+
+// follow checks value v that node from gets its error from (by edge of kind)
+func (w *walker) follow(from graphNode, edge string, v ssa.Value, defaultPos token.Pos, p path) {
+	w.graph.addEdge(from, v, edge)
+	current := w.current
+	w.current = v
+	w.allowedValue(v, defaultPos, p)
+	w.current = current
+}
+
+// unknownf records unsupported case, reports it only with ReportUnknown
+func (w *walker) unknownf(pos token.Pos, p path, format string, args ...interface{}) {
+	if !w.cfg.ReportUnknown {
+		w.findings++
+		if w.deferred != nil {
+			*w.deferred = append(*w.deferred, func() {
+				w.unknownf(pos, p, format, args...)
+			})
+			return
+		}
+		w.site.add(RuleUnsupported)
+		w.addSource(ErrorSource{Unknown: RuleUnsupported})
+		return
+	}
+	w.reportf(RuleUnsupported, pos, p, "", format, args...)
+}
+
+func (w *walker) checkCallInstruction(v ssa.CallInstruction, defaultPos token.Pos, p path) {
 	//https://godoc.org/golang.org/x/tools/go/ssa#CallCommon
 	commonCall := v.Common()
 	if commonCall.IsInvoke() {
 		//call to interface method
+		if c := contractOf(w.pass, w.res, commonCall.Method); c != nil {
+			w.checkContractErrors(c, commonCall.Method, retPos(v, defaultPos), p)
+			return
+		}
+		if w.checkUnwrapCall(v, defaultPos, p) {
+			return
+		}
 		pkgName := commonCall.Method.Pkg().Path()
-		if isOurPkg(pkgName, cfg) {
+		w.addSource(ErrorSource{Unknown: "method " + pkgName + "." + commonCall.Method.Name()})
+		if isOurPkg(pkgName, w.cfg) {
+			w.allowed("OurPackages", "method of our pkg %s", pkgName)
 			return
 		}
-		reportf(pass, retPos(v, defaultPos), "error not from our pkg: %s", pkgName)
+		w.reportf(RuleForeignCall, retPos(v, defaultPos), p, pkgName+"."+commonCall.Method.Name(), "error not from our pkg: %s", pkgName)
 		return
 	}
 	function := commonCall.StaticCallee()
 	if function != nil {
-		if ok, wrappedErr := isWrapCall(commonCall, cfg); ok {
-			// check that wrapped error is allowed
-			allowedValue(pass, wrappedErr, cfg, retPos(v, defaultPos), seen)
+		if w.checkUnwrapCall(v, defaultPos, p) {
+			return
+		}
+		if ok, joined := isJoinCall(commonCall, w.cfg); ok {
+			// check that every (or any) joined error is allowed
+			// error set of join function itself is ignored: it is set of joined errors
+			pos := retPos(v, defaultPos)
+			w.wrapped++
+			w.checkJoined(function, joined, pos, p)
+			w.wrapped--
 			return
-		} //else {
-		//reportf(pass, retPos(v, defaultPos), "not wrap: %v", commonCall.StaticCallee().Pkg.Pkg.Path() +  )
-		//}
+		}
+		if ok, wrappedErr := isWrapCall(commonCall, w.cfg); ok {
+			if w.checkWrapped(function, wrappedErr, retPos(v, defaultPos), p) {
+				return
+			}
+		}
 		// (a) statically dispatched call to a package-level function, an anonymous function, or a method of a named type
 		// (b) immediately applied function literal with free variables
-		pkgName := function.Pkg.Pkg.Path()
-		if isOurPkg(pkgName, cfg) {
+		pkgName := funcPkgPath(function)
+		w.addCallSource(function, false)
+		if isOurPkg(pkgName, w.cfg) {
+			w.allowed("OurPackages", "function of our pkg %s", pkgName)
 			return
 		}
-		reportf(pass, retPos(v, defaultPos), "error not from our pkg: %s", pkgName)
+		w.reportf(RuleForeignCall, retPos(v, defaultPos), p, function.String(), "error not from our pkg: %s", pkgName)
 		return
 	}
 	if blt, ok := commonCall.Value.(*ssa.Builtin); ok {
-		reportf(pass, retPos(v, defaultPos), "error not from our pkg: builtin %s", blt.Name())
+		w.addSource(ErrorSource{Unknown: "builtin " + blt.Name()})
+		w.reportf(RuleForeignCall, retPos(v, defaultPos), p, blt.Name(), "error not from our pkg: builtin %s", blt.Name())
 		return
 	}
 	// (d) any other value, indicating a dynamically dispatched function call.
 	// not supported - we cant even check pkg for it
-	reportf(pass, retPos(v, defaultPos), "dynamically dispatched function call: %v", commonCall)
+	w.addSource(ErrorSource{Unknown: "dynamic call " + commonCall.Value.String()})
+	w.reportf(RuleDynamicCall, retPos(v, defaultPos), p, commonCall.Value.String(), "dynamically dispatched function call: %v", commonCall)
+}
+
+// checkContractErrors checks errors declared in contract of called interface method.
+// Caller trusts contract: implementations are checked against it
+func (w *walker) checkContractErrors(c *contract, method *types.Func, pos token.Pos, p path) {
+	reported := false
+	for _, e := range c.Errors {
+		w.addSource(ErrorSource{Type: e.Type, Sentinel: e.Sentinel, Pkg: e.Pkg, Via: []string{method.FullName()}})
+		switch {
+		case e.Type != "" && !isAllowedTypeName(e.Type, w.cfg):
+			w.reportf(RuleDisallowedType, pos, p, e.Type, "not our type error: %s (declared in contract of %s)", e.Type, method.FullName())
+			reported = true
+		case e.Sentinel != "" && !isOurPkg(e.Sentinel[:strings.LastIndex(e.Sentinel, ".")], w.cfg):
+			w.reportf(RuleForeignCall, pos, p, e.Sentinel, "error not from our pkg: %s (declared in contract of %s)", e.Sentinel, method.FullName())
+			reported = true
+		}
+	}
+	if !reported {
+		w.allowed("", "declared in contract of %s", method.FullName())
+	}
 }
 
 // check if error value is allowed
 // if error is returned if value is unsupperted as of yet
 // defaultPos - pos to return in case value has no pos (const)
-func allowedValue(pass *analysis.Pass, v ssa.Value, cfg *Config, defaultPos token.Pos, seen map[ssa.Value]bool) {
-	if seen[v] {
+// p - steps from return to v
+func (w *walker) allowedValue(v ssa.Value, defaultPos token.Pos, p path) {
+	if hasFindings, ok := w.seen[v]; ok {
+		if hasFindings {
+			// findings were reported when value was checked first
+			w.findings++
+		}
+		return
+	}
+	w.seen[v] = false
+	findings := w.findings
+	defer func() {
+		w.seen[v] = w.findings > findings
+	}()
+	if w.checkAsTarget(v, defaultPos, p) {
 		return
 	}
-	seen[v] = true
 	if v.Type() == errorType {
 		// "error" type
 		// follow from where we got that interface
@@ -318,88 +625,141 @@ func allowedValue(pass *analysis.Pass, v ssa.Value, cfg *Config, defaultPos toke
 		// - from global - not ok
 		switch v := v.(type) {
 		case *ssa.MakeInterface: // var err error = sometype{}
-			allowedValue(pass, v.X, cfg, retPos(v, defaultPos), seen)
+			w.follow(v, "make-interface", v.X, retPos(v, defaultPos), p)
 		case *ssa.ChangeType:
-			allowedValue(pass, v.X, cfg, retPos(v, defaultPos), seen)
+			pos := retPos(v, defaultPos)
+			w.follow(v, "change-type", v.X, pos, p.with(pos, "converted from "+v.X.Type().String()))
+		case *ssa.ChangeInterface: // var err error = netErr
+			if _, calls := asTarget(v.X); len(calls) == 0 {
+				// only targets of errors.As are followed
+				w.unknownf(retPos(v, defaultPos), p, "unsupported case for error value: %s", valueKind(v))
+				return
+			}
+			pos := retPos(v, defaultPos)
+			w.follow(v, "change-interface", v.X, pos, p.with(pos, "converted from "+v.X.Type().String()))
 		case *ssa.Phi: // alternatives
+			pos := retPos(v, defaultPos)
 			for _, altV := range v.Edges {
-				allowedValue(pass, altV, cfg, retPos(v, defaultPos), seen)
+				w.follow(v, "phi", altV, pos, p.with(pos, "merged from branches"))
 			}
 		case ssa.CallInstruction:
-			checkCallInstruction(pass, v, cfg, defaultPos, seen)
+			w.checkCallInstruction(v, defaultPos, p)
+		case *ssa.TypeAssert: // err = r.(error)
+			w.checkAsserted(v, defaultPos, p)
 		case *ssa.Extract:
 			switch tuple := v.Tuple.(type) {
 			case ssa.CallInstruction:
-				checkCallInstruction(pass, tuple, cfg, defaultPos, seen)
+				w.graph.addEdge(v, tuple, "extract")
+				w.current = tuple
+				w.checkCallInstruction(tuple, defaultPos, p)
+				w.current = v
+				return
+			case *ssa.TypeAssert: // e, ok := r.(error)
+				w.graph.addEdge(v, tuple, "extract")
+				w.current = tuple
+				w.checkAsserted(tuple, defaultPos, p)
+				w.current = v
 				return
 			default:
-				if cfg.ReportUnknown {
-					reportf(pass, retPos(v, defaultPos), "[warn] unsupported case for extract value=%#v", v)
-				}
+				w.unknownf(retPos(v, defaultPos), p, "unsupported case for extract value: %s", valueKind(v))
 			}
 		case *ssa.Lookup: // err = somemap[key]
 			// cant check all errors in map (especially for global var)
-			reportf(pass, retPos(v, defaultPos), "not our type error in map lookup: %s", v.Type().String())
+			w.reportf(RuleMapLookup, retPos(v, defaultPos), p, v.X.Type().String(), "not our type error in map lookup: %s", v.Type().String())
 		case *ssa.UnOp:
 			if v.Op == token.MUL {
 				switch xValue := v.X.(type) {
 				case *ssa.Global:
 					// use of global var
-					reportf(pass, retPos(v, defaultPos), "cant check error type for global: %s", xValue.Name())
+					w.addSource(ErrorSource{Sentinel: xValue.RelString(nil), Pkg: xValue.Pkg.Pkg.Path()})
+					if w.exemption != nil && w.exemption.allows(xValue.RelString(nil)) {
+						w.allowed("InterfaceExemptions", "allowed var %s for implementation of %s", xValue.RelString(nil), strings.Join(w.exemption.ifaces, ", "))
+						return
+					}
+					w.reportf(RuleGlobal, retPos(v, defaultPos), p, xValue.RelString(nil), "cant check error type for global: %s", xValue.Name())
 				case *ssa.Alloc:
 					for _, instr := range *xValue.Referrers() {
-						if store, ok := instr.(*ssa.Store); ok {
-							allowedValue(pass, store.Val, cfg, retPos(store, defaultPos), seen)
+						switch instr := instr.(type) {
+						case *ssa.Store:
+							pos := retPos(instr, defaultPos)
+							w.follow(v, "store", instr.Val, pos, p.with(pos, "assigned"))
+						case *ssa.MakeClosure:
+							// var is captured and may be assigned by closure (deferred recover)
+							w.followCaptured(v, instr, xValue, defaultPos, p)
 						}
 					}
 				case *ssa.FreeVar:
 					for _, instr := range *xValue.Referrers() {
 						if store, ok := instr.(*ssa.Store); ok {
-							allowedValue(pass, store.Val, cfg, retPos(store, defaultPos), seen)
+							pos := retPos(store, defaultPos)
+							w.follow(v, "free-var-store", store.Val, pos, p.with(pos, "assigned to captured variable "+xValue.Name()))
 						}
 					}
 				case *ssa.FieldAddr:
-					reportf(pass, retPos(v, defaultPos), "cant check error type for struct field")
+					w.reportf(RuleStructField, retPos(v, defaultPos), p, "", "cant check error type for struct field")
 				case *ssa.IndexAddr:
-					reportf(pass, retPos(v, defaultPos), "cant check error type for slice element")
+					w.reportf(RuleSliceElement, retPos(v, defaultPos), p, "", "cant check error type for slice element")
 				default:
-					reportf(pass, retPos(v, defaultPos), "[warn] unsupported case for error from UnOp(MUL) with value=%#v", xValue)
+					w.reportf(RuleUnsupported, retPos(v, defaultPos), p, "", "unsupported case for error from UnOp(MUL) with value: %s", valueKind(xValue))
 				}
 				return
 			}
-			if cfg.ReportUnknown {
-				reportf(pass, retPos(v, defaultPos), "[warn] unsupported case for error value=%#v", v)
-			}
+			w.unknownf(retPos(v, defaultPos), p, "unsupported case for error value: %s", valueKind(v))
 		case *ssa.Const:
 			if v.Value == constant.Value(nil) {
 				//nill error interface
+				w.allowed("", "nil")
 				return
 			}
-			reportf(pass, retPos(v, defaultPos), "[warn] unsupported case for error const=%#v", v)
+			w.reportf(RuleUnsupported, retPos(v, defaultPos), p, "", "unsupported case for error const: %s", valueKind(v))
 		case *ssa.Parameter:
-			reportf(pass, retPos(v, defaultPos), "cant check error type for %v", v)
+			w.reportf(RuleParameter, retPos(v, defaultPos), p, v.Name(), "cant check error type for %v", v)
 		default:
 			//ssa.Field - unsupported - would not be able to check it if it has X=*ssa.Call (error from struct returned by other func)
-			if cfg.ReportUnknown {
-				reportf(pass, retPos(v, defaultPos), "[warn] unsupported case for error value=%#v", v)
-			}
+			w.unknownf(retPos(v, defaultPos), p, "unsupported case for error value: %s", valueKind(v))
+		}
+		return
+	}
+	w.addSource(ErrorSource{Type: v.Type().String(), Pkg: typePkgPath(v.Type())})
+	if w.exemption != nil {
+		// errors of interface convention replace AllowedTypes
+		if w.exemption.allows(v.Type().String()) {
+			w.allowed("InterfaceExemptions", "allowed type %s for implementation of %s", v.Type().String(), strings.Join(w.exemption.ifaces, ", "))
+			return
 		}
+	} else if isAllowedErrorType(v.Type(), w.cfg) {
+		w.allowed("AllowedTypes", "allowed type %s", v.Type().String())
 		return
 	}
-	if isAllowedErrorType(v.Type(), cfg) {
+	if w.exemption != nil {
+		w.reportSettingf(RuleDisallowedType, "InterfaceExemptions", retPos(v, defaultPos), p, v.Type().String(), "not our type error: %s (errors of implementation of %s are set by InterfaceExemptions)",
+			v.Type().String(), strings.Join(w.exemption.ifaces, ", "))
 		return
 	}
-	reportf(pass, retPos(v, defaultPos), "not our type error: %s", v.Type().String())
+	w.reportf(RuleDisallowedType, retPos(v, defaultPos), p, v.Type().String(), "not our type error: %s", v.Type().String())
 }
 
-func runFunc(pass *analysis.Pass, fn *ssa.Function, cfg *Config) {
+func runFunc(pass *analysis.Pass, fn *ssa.Function, cfg *Config, res *Result) {
 	errorsAtReturn := errorsBySignature(fn.Signature)
-	if len(errorsAtReturn) == 0 {
+	fieldsAtReturn := fieldsBySignature(fn.Signature, pass.Pkg, cfg)
+	funcsAtReturn := funcsBySignature(fn.Signature)
+	if len(errorsAtReturn) == 0 && len(fieldsAtReturn) == 0 && len(funcsAtReturn) == 0 && len(cfg.Sinks) == 0 {
 		// function doen not return error
 		// will not check it
 		return
 	}
+	if isUnwrapMethod(fn, cfg) && !unwrapDeclared(pass, res, fn) {
+		// may return foreign cause, calls of it are checked instead
+		return
+	}
 
+	w := &walker{pass: pass, cfg: cfg, fn: fn, res: res, exemption: exemptionOf(res, fn), summarize: cfg.BoundaryOnly && !isBoundary(fn),
+		reported: make(map[reportKey]bool)}
+	if graphRequested(pass.Fset, fn, cfg) {
+		w.graph = newGraph(fn)
+		res.Graphs = append(res.Graphs, w.graph)
+	}
+	recovered := recoverDefer(fn)
 	seen := make([]bool, len(fn.Blocks)) // seen[i] means visit should ignore block i
 	var visit func(b *ssa.BasicBlock)
 	visit = func(b *ssa.BasicBlock) {
@@ -408,13 +768,30 @@ func runFunc(pass *analysis.Pass, fn *ssa.Function, cfg *Config) {
 		}
 		seen[b.Index] = true
 		for _, instr := range b.Instrs {
-			if retInstr, ok := instr.(*ssa.Return); ok {
+			if retInstr, ok := instr.(*ssa.Return); ok && len(errorsAtReturn)+len(fieldsAtReturn)+len(funcsAtReturn) > 0 {
 				operands := retInstr.Operands([]*ssa.Value(nil))
+				pos, step := retPos(retInstr, fn.Pos()), "returned"
+				if b == fn.Recover {
+					// return after panic recovered by deferred call
+					pos, step = recovered.Pos(), "returned after recovered panic"
+				}
+				w.site = &ReturnSite{Pos: pos, Func: fn.RelString(nil), Class: ClassAllowed}
 				for _, i := range errorsAtReturn {
 					value := operands[i]
-					seenValue := make(map[ssa.Value]bool)
-					allowedValue(pass, *value, cfg, retInstr.Pos(), seenValue)
+					w.seen = make(map[ssa.Value]bool)
+					w.follow(retInstr, "return", *value, pos, path{{pos, step}})
+				}
+				for _, i := range fieldsAtReturn {
+					w.checkFields(retInstr, *operands[i])
 				}
+				for _, i := range funcsAtReturn {
+					w.seen = make(map[ssa.Value]bool)
+					w.checkFuncs(retInstr, *operands[i])
+				}
+				res.Sites = append(res.Sites, *w.site)
+			}
+			if name, args := sinkArgs(instr, pass.Pkg, cfg); len(args) > 0 {
+				w.checkSink(instr, name, args)
 			}
 		}
 
@@ -423,8 +800,16 @@ func runFunc(pass *analysis.Pass, fn *ssa.Function, cfg *Config) {
 		}
 	}
 
-	// Visit the entry block.  No need to visit fn.Recover.
+	// Visit the entry block and fn.Recover if panic can be recovered
 	if fn.Blocks != nil {
 		visit(fn.Blocks[0])
 	}
+	if fn.Recover != nil && recovered != nil {
+		w.afterPanic = true
+		visit(fn.Recover)
+	}
+	if len(errorsAtReturn) > 0 || len(funcsAtReturn) > 0 {
+		// errors of returned closures are errors of function too
+		res.sources[fn] = w.sources
+	}
 }
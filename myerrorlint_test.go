@@ -2,6 +2,7 @@
 package myerrorlint_test
 
 import (
+	"strings"
 	"testing"
 
 	linter "github.com/Rikkuru/myerrorlint"
@@ -18,3 +19,334 @@ func Test(t *testing.T) {
 		WrapFuncWithFirstArgError: []string{"a.Wrap"}})
 	analysistest.Run(t, testdata, analizer, "a")
 }
+
+func TestRules(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"rules.myError"},
+		OurPackages:  []string{"rules"},
+		Rules: map[string]linter.RuleConfig{
+			linter.RuleGlobal:    {Disabled: true},
+			linter.RuleParameter: {Severity: linter.SeverityWarning},
+		}})
+	results := analysistest.Run(t, testdata, analizer, "rules")
+	for _, res := range results {
+		for _, diag := range res.Diagnostics {
+			if diag.Category != linter.RuleParameter {
+				t.Errorf("unexpected category %q of %q", diag.Category, diag.Message)
+			}
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := linter.ParseRules([]string{"global:off", "unsupported:error", "parameter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rules[linter.RuleGlobal].Disabled {
+		t.Errorf("global rule should be disabled")
+	}
+	if rules[linter.RuleUnsupported].Severity != linter.SeverityError {
+		t.Errorf("unsupported rule should have error severity")
+	}
+	if _, err := linter.ParseRules([]string{"no-such-rule:off"}); err == nil {
+		t.Errorf("expected error for unknown rule")
+	}
+	if _, err := linter.ParseRules([]string{"global:fatal"}); err == nil {
+		t.Errorf("expected error for bad severity")
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"provenance.myError"},
+		OurPackages:  []string{"provenance"}})
+	results := analysistest.Run(t, testdata, analizer, "provenance")
+	for _, res := range results {
+		findings := res.Result.(*linter.Result).Findings
+		if len(findings) != 2 {
+			t.Fatalf("expected 2 findings, got %d", len(findings))
+		}
+		for _, f := range findings {
+			if f.Origin != "b.F" {
+				t.Errorf("unexpected origin %q", f.Origin)
+			}
+			last := f.Related[len(f.Related)-1]
+			if last.Message != "returned" {
+				t.Errorf("path should end with return, got %q", last.Message)
+			}
+		}
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"coverage.myError"},
+		OurPackages:  []string{"coverage"}})
+	results := analysistest.Run(t, testdata, analizer, "coverage")
+	expected := map[string][]string{
+		"coverage.fAllowed":      {linter.ClassAllowed},
+		"coverage.fViolation":    {linter.ClassViolation},
+		"coverage.fUnverifiable": {linter.ClassUnverifiable},
+		"coverage.fUnsupported":  {linter.ClassUnsupported},
+		"coverage.fTwoSites":     {linter.ClassViolation, linter.ClassAllowed},
+	}
+	for _, res := range results {
+		classes := make(map[string][]string)
+		for _, site := range res.Result.(*linter.Result).Sites {
+			classes[site.Func] = append(classes[site.Func], site.Class)
+		}
+		for fn, expectedClasses := range expected {
+			if strings.Join(classes[fn], ",") != strings.Join(expectedClasses, ",") {
+				t.Errorf("%s: expected sites %v, got %v", fn, expectedClasses, classes[fn])
+			}
+		}
+	}
+}
+
+func TestGraph(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"provenance.myError"},
+		OurPackages:  []string{"provenance"},
+		GraphFuncs:   []string{"provenance.fErrorThroughPhi"}})
+	results := analysistest.Run(t, testdata, analizer, "provenance")
+	for _, res := range results {
+		graphs := res.Result.(*linter.Result).Graphs
+		if len(graphs) != 1 || graphs[0].Func != "provenance.fErrorThroughPhi" {
+			t.Fatalf("expected graph of fErrorThroughPhi, got %v", graphs)
+		}
+		verdicts := make(map[string]bool)
+		for _, n := range graphs[0].Nodes {
+			verdicts[n.Verdict] = true
+		}
+		if !verdicts[linter.VerdictAllowed] || !verdicts[linter.RuleForeignCall] {
+			t.Errorf("expected allowed and foreign-call leaves, got %+v", graphs[0].Nodes)
+		}
+		phiEdges := 0
+		for _, e := range graphs[0].Edges {
+			if e.Kind == "phi" {
+				phiEdges++
+			}
+		}
+		if phiEdges != 2 {
+			t.Errorf("expected 2 phi edges, got %+v", graphs[0].Edges)
+		}
+	}
+}
+
+func TestErrorSets(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"errorsets.myError"},
+		OurPackages:  []string{"errorsets"}})
+	results := analysistest.Run(t, testdata, analizer, "errorsets")
+	for _, res := range results {
+		for _, fn := range res.Result.(*linter.Result).Funcs {
+			if fn.Func != "errorsets.Get" {
+				continue
+			}
+			if len(fn.Errors) != 2 {
+				t.Fatalf("expected 2 errors of Get, got %+v", fn.Errors)
+			}
+			sentinel := fn.Errors[0]
+			if sentinel.Sentinel != "errorsets.ErrNotFound" || strings.Join(sentinel.Via, ",") != "errorsets.helper" {
+				t.Errorf("unexpected error source %+v", sentinel)
+			}
+		}
+	}
+}
+
+func TestContracts(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"*contracts.NotFoundError", "contracts.ConflictError"},
+		OurPackages:  []string{"contracts"}})
+	analysistest.Run(t, testdata, analizer, "contracts")
+}
+
+func TestInterfaceExemptions(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"exemptions.myError"},
+		OurPackages:  []string{"exemptions"},
+		InterfaceExemptions: []linter.InterfaceExemption{
+			{Interface: "io.Reader", Errors: []string{"io.EOF"}},
+		}})
+	analysistest.Run(t, testdata, analizer, "exemptions")
+}
+
+func TestLayers(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		OurPackages: []string{"b", "layers/"},
+		Layers: []linter.LayerRule{
+			{Origin: "b", OriginPkgs: []string{"b", ""}, Target: "api", TargetPkgs: []string{"", "layers/api"}},
+		}})
+	analysistest.Run(t, testdata, analizer, "layers/repo", "layers/api")
+}
+
+func TestSinks(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"sinks.myError"},
+		OurPackages:  []string{"sinks"},
+		Sinks: []linter.Sink{
+			{Func: "sinks.report", Arg: 1},
+			{Func: "log.Printf", Arg: 1},
+			{Func: "panic"},
+			{Func: "(*encoding/json.Encoder).Encode", Arg: 0},
+		}})
+	analysistest.Run(t, testdata, analizer, "sinks")
+}
+
+func TestFields(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"fields.myError"},
+		OurPackages:  []string{"fields"}})
+	analysistest.Run(t, testdata, analizer, "fields")
+}
+
+func TestClosures(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"closures.myError"},
+		OurPackages:  []string{"closures"}})
+	analysistest.Run(t, testdata, analizer, "closures")
+
+	// closures of analyzed package are ours without OurPackages
+	analizer = linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"closurepkg.myError"}})
+	analysistest.Run(t, testdata, analizer, "closurepkg")
+}
+
+func TestJoin(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"join.myError"},
+		OurPackages:  []string{"join"},
+		JoinFuncs:    []string{"errors.Join", "join.Combine"}})
+	analysistest.Run(t, testdata, analizer, "join")
+
+	analizer = linter.NewAnalyzer(linter.Config{
+		AllowedTypes:    []string{"joinany.myError"},
+		OurPackages:     []string{"joinany"},
+		AllowErrorfWrap: true,
+		JoinFuncs:       []string{"errors.Join"},
+		JoinMode:        linter.JoinAny})
+	analysistest.Run(t, testdata, analizer, "joinany")
+}
+
+func TestChainMode(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"chainas.myError"},
+		OurPackages:  []string{"chainas"},
+		ChainMode:    linter.ChainAs,
+		WrapFuncs: []linter.WrapFunc{
+			{Func: "b.Annotate"},
+			{Func: "chainas.wrap", AddsLayer: true},
+		}})
+	analysistest.Run(t, testdata, analizer, "chainas")
+
+	analizer = linter.NewAnalyzer(linter.Config{
+		AllowedTypes:    []string{"chaintop.myError"},
+		OurPackages:     []string{"chaintop"},
+		AllowErrorfWrap: true,
+		ChainMode:       linter.ChainTop,
+		WrapFuncs: []linter.WrapFunc{
+			{Func: "b.Annotate", AddsLayer: true},
+			{Func: "b.WithStack"},
+		}})
+	analysistest.Run(t, testdata, analizer, "chaintop")
+}
+
+func TestCauses(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes:    []string{"causes.myError", "*causes.withCause", "*causes.unwrapped"},
+		OurPackages:     []string{"causes"},
+		AllowErrorfWrap: true,
+		Rules: map[string]linter.RuleConfig{
+			linter.RuleParameter:   {Disabled: true},
+			linter.RuleForeignCall: {Disabled: true},
+			linter.RuleStructField: {Disabled: true},
+		}})
+	analysistest.Run(t, testdata, analizer, "causes")
+}
+
+func TestAllowedTypes(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{
+			"allowedtypes.ptrError", "allowedtypes.notError", "allowedtypes.badMethods", "*allowedtypes.badMethods",
+			"allowedtypes.multi", "allowedtypes.recursive", "allowedtypes.byCode", "*allowedtypes.ptrRecursive",
+			"allowedtypes.goSyntax", "allowedtypes.hexRecursive", "*allowedtypes.upperHexRecursive",
+		},
+		OurPackages: []string{"allowedtypes"},
+		Rules:       map[string]linter.RuleConfig{linter.RuleStructField: {Disabled: true}}})
+	analysistest.Run(t, testdata, analizer, "allowedtypes")
+}
+
+func TestUnwrap(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"*unwrap.myError", "*unwrap.declared"},
+		OurPackages:  []string{"unwrap"},
+		InterfaceExemptions: []linter.InterfaceExemption{
+			{Interface: "unwrap.wrapper", Errors: []string{"*unwrap.myError"}},
+		}})
+	analysistest.Run(t, testdata, analizer, "unwrap")
+}
+
+func TestAsTarget(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"*astarget.myError"},
+		OurPackages:  []string{"astarget"}})
+	analysistest.Run(t, testdata, analizer, "astarget")
+}
+
+func TestRecovered(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"*recovered.myError"},
+		OurPackages:  []string{"recovered"}})
+	analysistest.Run(t, testdata, analizer, "recovered")
+}
+
+func TestBoundary(t *testing.T) {
+	testdata := analysistest.TestData()
+	analizer := linter.NewAnalyzer(linter.Config{
+		AllowedTypes: []string{"*boundary.myError"},
+		OurPackages:  []string{"boundary"},
+		BoundaryOnly: true})
+	analysistest.Run(t, testdata, analizer, "boundary")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := linter.Config{WrapFuncs: []linter.WrapFunc{{Func: "b.Annotate", Arg: -1}}}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("expected error for negative Arg of wrap function")
+	}
+	cfg.WrapFuncs[0].Arg = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cfg.JoinMode = "one"
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("expected error for unknown JoinMode")
+	}
+	cfg.JoinMode, cfg.ChainMode = linter.JoinAny, "all"
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("expected error for unknown ChainMode")
+	}
+	cfg.ChainMode = linter.ChainAs
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
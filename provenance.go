@@ -0,0 +1,112 @@
+package myerrorlint
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// step is one hop of error value on its way from origin to return
+type step struct {
+	Pos  token.Pos
+	What string // "returned", "assigned", "merged from branches", ...
+}
+
+// path of error value from return (first) to current value (last)
+type path []step
+
+// with returns copy of path with step added; steps without position are skipped
+func (p path) with(pos token.Pos, what string) path {
+	if pos == token.NoPos {
+		return p
+	}
+	res := make(path, len(p), len(p)+1)
+	copy(res, p)
+	return append(res, step{Pos: pos, What: what})
+}
+
+// related returns path in order from origin to return
+func (p path) related() []RelatedInformation {
+	res := make([]RelatedInformation, 0, len(p))
+	for i := len(p) - 1; i >= 0; i-- {
+		res = append(res, RelatedInformation{Pos: p[i].Pos, Message: p[i].What})
+	}
+	return res
+}
+
+// RelatedInformation is a step of provenance of reported error.
+// Same as analysis.RelatedInformation of newer x/tools (our version has no Related in Diagnostic)
+type RelatedInformation struct {
+	Pos     token.Pos
+	Message string
+}
+
+// Finding is a reported error with its provenance
+type Finding struct {
+	Pos      token.Pos
+	Rule     string
+	Severity Severity
+	Message  string // without severity prefix and path
+	Func     string // function with return the error comes to
+	Origin   string // callee, type or variable the error originates from
+	Related  []RelatedInformation
+}
+
+// Result of analyzer for a package
+type Result struct {
+	Findings []Finding
+	Sites    []ReturnSite // all checked returns for coverage report
+	Graphs   []*Graph     // for functions from Config.GraphFuncs
+	Funcs    []FuncErrors // errors every function returning error can return
+
+	sources    map[*ssa.Function][]leafSource
+	contracts  []ifaceContract             // contracts of interface methods declared in package or imported
+	exemptions []ifaceExemption            // InterfaceExemptions with interfaces found
+	factories  map[*ssa.Function][]string  // functions returning closure by closure
+	summaries  map[*ssa.Function][]Finding // findings of functions not on boundary with BoundaryOnly
+}
+
+// reportFinding reports finding if its rule is not disabled
+// Diagnostic of our x/tools has no Related field so path is added to message
+func reportFinding(pass *analysis.Pass, cfg *Config, res *Result, f Finding) {
+	f.Severity = ruleSeverity(f.Rule, cfg)
+	if f.Severity == SeverityOff {
+		return
+	}
+	res.Findings = append(res.Findings, f)
+	message := f.Message
+	if f.Severity == SeverityWarning {
+		message = "[warn] " + message
+	}
+	if trail := pathTrail(pass.Fset, f.Pos, f.Related); trail != "" {
+		message += " (" + trail + ")"
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:      f.Pos,
+		Category: f.Rule,
+		Message:  message,
+	})
+}
+
+// pathTrail formats related steps like "assigned at a.go:12 -> returned at a.go:15"
+// Empty if all steps are on the line of the finding
+func pathTrail(fset *token.FileSet, pos token.Pos, related []RelatedInformation) string {
+	line := fset.Position(pos).Line
+	otherLine := false
+	items := make([]string, 0, len(related))
+	for _, r := range related {
+		position := fset.Position(r.Pos)
+		if position.Line != line {
+			otherLine = true
+		}
+		items = append(items, fmt.Sprintf("%s at %s:%d", r.Message, filepath.Base(position.Filename), position.Line))
+	}
+	if !otherLine {
+		return ""
+	}
+	return strings.Join(items, " -> ")
+}
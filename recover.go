@@ -0,0 +1,88 @@
+package myerrorlint
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// recoverDefer returns deferred call of function that calls recover(), nil if there is none.
+// If panic is recovered function returns from fn.Recover block
+func recoverDefer(fn *ssa.Function) *ssa.Defer {
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			d, ok := instr.(*ssa.Defer)
+			if !ok {
+				continue
+			}
+			callee := d.Call.StaticCallee()
+			if closure, ok := d.Call.Value.(*ssa.MakeClosure); ok {
+				callee, _ = closure.Fn.(*ssa.Function)
+			}
+			if callee != nil && callsRecover(callee) {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+func callsRecover(fn *ssa.Function) bool {
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			if call, ok := instr.(*ssa.Call); ok && isRecovered(call) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isDeferred(closure *ssa.MakeClosure) bool {
+	for _, instr := range *closure.Referrers() {
+		if d, ok := instr.(*ssa.Defer); ok && d.Call.Value == closure {
+			return true
+		}
+	}
+	return false
+}
+
+// isRecovered checks if value is result of recover()
+func isRecovered(v ssa.Value) bool {
+	call, ok := v.(*ssa.Call)
+	if !ok {
+		return false
+	}
+	blt, ok := call.Call.Value.(*ssa.Builtin)
+	return ok && blt.Name() == "recover"
+}
+
+// followCaptured checks values stored to variable alloc by deferred closure it is captured by.
+// Deferred closure runs after return value is assigned, other closures are checked by calls of them
+func (w *walker) followCaptured(from ssa.Value, closure *ssa.MakeClosure, alloc *ssa.Alloc, defaultPos token.Pos, p path) {
+	fn, ok := closure.Fn.(*ssa.Function)
+	if !ok || !isDeferred(closure) {
+		return
+	}
+	for i, binding := range closure.Bindings {
+		if binding != alloc {
+			continue
+		}
+		for _, instr := range *fn.FreeVars[i].Referrers() {
+			if store, ok := instr.(*ssa.Store); ok && store.Addr == fn.FreeVars[i] {
+				pos := retPos(store, defaultPos)
+				w.follow(from, "closure-store", store.Val, pos, p.with(pos, "assigned in deferred closure"))
+			}
+		}
+	}
+}
+
+// checkAsserted reports error asserted from recovered panic value: it can be any error
+func (w *walker) checkAsserted(assert *ssa.TypeAssert, defaultPos token.Pos, p path) {
+	if !isRecovered(assert.X) {
+		w.unknownf(retPos(assert, defaultPos), p, "unsupported case for error value: %s", valueKind(assert))
+		return
+	}
+	w.addSource(ErrorSource{Unknown: "recovered panic value"})
+	w.reportf(RuleForeignCall, retPos(assert, defaultPos), p, "recover", "error not from our pkg: recovered panic value")
+}
@@ -0,0 +1,123 @@
+package myerrorlint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule IDs. They are put to analysis.Diagnostic.Category and must stay stable:
+// users refer to them in config and in nolint/baseline files
+const (
+	RuleForeignCall    = "foreign-call"    // error returned from function of not our pkg
+	RuleDisallowedType = "disallowed-type" // error of type that is not in AllowedTypes
+	RuleGlobal         = "global"          // error from global var
+	RuleMapLookup      = "map-lookup"      // error from map of error interfaces
+	RuleStructField    = "struct-field"    // error from struct field of error interface type
+	RuleSliceElement   = "slice-element"   // error from slice of error interfaces
+	RuleParameter      = "parameter"       // error from function parameter
+	RuleDynamicCall    = "dynamic-call"    // error from dynamically dispatched function call
+	RuleUnsupported    = "unsupported"     // case linter does not know how to check
+
+	RuleUndeclaredError = "undeclared-error" // error function can return is not declared in its contract
+	RuleUnproducedError = "unproduced-error" // error declared in contract is never returned
+	RuleLayer           = "layer"            // error of layer is returned from package of layer it must not escape to
+	RuleCauseDropped    = "cause-dropped"    // error is built from message of other error dropping it from chain
+	RuleAllowedType     = "allowed-type"     // allowed type is not a sound error type
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityOff     Severity = "off" // same as Disabled
+)
+
+const docURL = "https://github.com/Rikkuru/myerrorlint#"
+
+// Rule describes one class of findings
+type Rule struct {
+	ID       string
+	Doc      string
+	URL      string
+	Severity Severity // default severity
+}
+
+var Rules = []Rule{
+	{RuleForeignCall, "error returned from function of not our package", docURL + RuleForeignCall, SeverityError},
+	{RuleDisallowedType, "error of type not listed in allowed types", docURL + RuleDisallowedType, SeverityError},
+	{RuleGlobal, "error from global variable can not be checked", docURL + RuleGlobal, SeverityError},
+	{RuleMapLookup, "error from map of error interfaces can not be checked", docURL + RuleMapLookup, SeverityError},
+	{RuleStructField, "error from struct field of error interface type can not be checked", docURL + RuleStructField, SeverityError},
+	{RuleSliceElement, "error from slice of error interfaces can not be checked", docURL + RuleSliceElement, SeverityError},
+	{RuleParameter, "error from function parameter can not be checked", docURL + RuleParameter, SeverityError},
+	{RuleDynamicCall, "error from dynamically dispatched function call can not be checked", docURL + RuleDynamicCall, SeverityError},
+	{RuleUnsupported, "case linter does not support yet", docURL + RuleUnsupported, SeverityWarning},
+	{RuleUndeclaredError, "error function can return is not declared in //myerrorlint:returns contract", docURL + RuleUndeclaredError, SeverityError},
+	{RuleUnproducedError, "error declared in //myerrorlint:returns contract is never returned", docURL + RuleUnproducedError, SeverityError},
+	{RuleLayer, "error of layer is returned from package it must not escape to", docURL + RuleLayer, SeverityError},
+	{RuleCauseDropped, "new error is built from message of other error that is not kept or unwrapped", docURL + RuleCauseDropped, SeverityWarning},
+	{RuleAllowedType, "allowed type does not implement error or has wrong Unwrap, Is, As or recursive Error method", docURL + RuleAllowedType, SeverityError},
+}
+
+func findRule(id string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// RuleConfig overrides defaults of one rule
+type RuleConfig struct {
+	Disabled bool
+	Severity Severity // empty means rule default
+}
+
+// ruleSeverity returns severity of rule with config applied, SeverityOff if rule is disabled
+func ruleSeverity(id string, cfg *Config) Severity {
+	rule, _ := findRule(id)
+	severity := rule.Severity
+	if ruleCfg, ok := cfg.Rules[id]; ok {
+		if ruleCfg.Disabled {
+			return SeverityOff
+		}
+		if ruleCfg.Severity != "" {
+			severity = ruleCfg.Severity
+		}
+	}
+	if severity == "" {
+		severity = SeverityError
+	}
+	return severity
+}
+
+// ParseRules parses rules from "id:severity" list (like "global:off,unsupported:error")
+func ParseRules(src []string) (map[string]RuleConfig, error) {
+	res := make(map[string]RuleConfig, len(src))
+	for _, item := range src {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, ":", 2)
+		id := parts[0]
+		if _, ok := findRule(id); !ok {
+			return nil, fmt.Errorf("unknown rule: %s", id)
+		}
+		if len(parts) == 1 {
+			res[id] = RuleConfig{}
+			continue
+		}
+		switch severity := Severity(parts[1]); severity {
+		case SeverityOff:
+			res[id] = RuleConfig{Disabled: true}
+		case SeverityError, SeverityWarning:
+			res[id] = RuleConfig{Severity: severity}
+		default:
+			return nil, fmt.Errorf("bad severity for rule %s: %s", id, parts[1])
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,112 @@
+package myerrorlint
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Sink is a function errors leave our code through besides return (like net/http.Error or log.Printf).
+// Errors passed to it are checked like returned ones
+type Sink struct {
+	Func string // qualified name like "net/http.Error", "(*log.Logger).Printf", "(pkg.Iface).Method" or "panic"
+	Arg  int    // index of argument, receiver is not counted. Every element of variadic argument is checked
+}
+
+// sinkArgs returns name of sink called by instruction and error values (or values holding errors) passed to it
+func sinkArgs(instr ssa.Instruction, pkg *types.Package, cfg *Config) (string, []ssa.Value) {
+	if len(cfg.Sinks) == 0 {
+		return "", nil
+	}
+	if panicInstr, ok := instr.(*ssa.Panic); ok {
+		for _, sink := range cfg.Sinks {
+			if sink.Func == "panic" {
+				return sink.Func, errorArgs(panicInstr.X, pkg, cfg)
+			}
+		}
+		return "", nil
+	}
+	call, ok := instr.(ssa.CallInstruction)
+	if !ok {
+		return "", nil
+	}
+	common := call.Common()
+	var name string
+	var sig *types.Signature
+	args := common.Args
+	if common.IsInvoke() {
+		name = common.Method.FullName()
+		sig = common.Method.Type().(*types.Signature)
+	} else if function := common.StaticCallee(); function != nil {
+		name = function.String()
+		sig = function.Signature
+		if sig.Recv() != nil {
+			// receiver is first arg
+			args = args[1:]
+		}
+	} else {
+		return "", nil
+	}
+	var values []ssa.Value
+	for _, sink := range cfg.Sinks {
+		if sink.Func != name || sink.Arg < 0 || sink.Arg >= len(args) {
+			continue
+		}
+		values = append(values, errorArgs(args[sink.Arg], pkg, cfg)...)
+	}
+	return name, values
+}
+
+// errorArgs returns errors passed as argument:
+// error itself, error converted to interface{}, receiver of Error() or elements of variadic ...interface{}.
+// Values with error fields (like errResp{err} passed to Encode) are returned to be checked like returned ones
+func errorArgs(v ssa.Value, pkg *types.Package, cfg *Config) []ssa.Value {
+	if isErrorType(v.Type()) || hasErrorPositions(v.Type(), pkg, cfg, make(map[types.Type]bool)) {
+		return []ssa.Value{v}
+	}
+	switch v := v.(type) {
+	case *ssa.MakeInterface:
+		return errorArgs(v.X, pkg, cfg)
+	case *ssa.ChangeInterface:
+		return errorArgs(v.X, pkg, cfg)
+	case *ssa.Call:
+		// err.Error()
+		common := v.Common()
+		if common.IsInvoke() {
+			if common.Method.Name() == "Error" && isErrorType(common.Value.Type()) {
+				return []ssa.Value{common.Value}
+			}
+			return nil
+		}
+		if function := common.StaticCallee(); function != nil && function.Name() == "Error" &&
+			function.Signature.Recv() != nil && len(common.Args) == 1 && isErrorType(common.Args[0].Type()) {
+			return []ssa.Value{common.Args[0]}
+		}
+	case *ssa.Slice:
+		// variadic args
+		var values []ssa.Value
+		for _, elem := range sliceElements(v) {
+			values = append(values, errorArgs(elem, pkg, cfg)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// checkSink checks errors passed to sink like returned ones.
+// They are not counted as return sites and not added to error set of function
+func (w *walker) checkSink(instr ssa.Instruction, name string, args []ssa.Value) {
+	sources, site := w.sources, w.site
+	w.site = nil
+	pos := retPos(instr, w.fn.Pos())
+	for _, v := range args {
+		w.seen = make(map[ssa.Value]bool)
+		if !isErrorType(v.Type()) {
+			// error fields of value
+			w.followValue(instr, v, path{{pos, "passed to " + name}}, make(map[ssa.Value]bool))
+			continue
+		}
+		w.follow(instr, "sink", v, pos, path{{pos, "passed to " + name}})
+	}
+	w.sources, w.site = sources, site
+}
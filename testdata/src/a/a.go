@@ -155,8 +155,8 @@ func fWithCorrectWrappedError() error {
 	return fmt.Errorf("err: %s, %w, %d", "str", myError(""), 11)
 }
 
-func Wrap(err error, msg string) error {
-	return myError(err.Error())
+func Wrap(err error, msg string) error { // want Wrap:"a.myError"
+	return myError(err.Error()) // want "cause dropped: only message of error parameter err is used"
 }
 
 func fWithCorrestWrappedError2() error {
@@ -244,3 +244,8 @@ func fIncorrectErrorFromFreeVar() (err error) {
 	}
 	return f()
 }
+
+// error interface passed to fmt.Errorf
+func fWithIncorrectWrappedErrorInterface() error {
+	return fmt.Errorf("err: %w", b.F()) // want "error not from our pkg: b"
+}
@@ -0,0 +1,91 @@
+// package for tests of checks of allowed types
+package allowedtypes
+
+import "fmt"
+
+type ptrError struct{} // want "allowed type allowedtypes.ptrError does not implement error: Error has pointer receiver, allow \\*allowedtypes.ptrError"
+
+func (*ptrError) Error() string {
+	return "ptr"
+}
+
+type notError struct{} // want "allowed type allowedtypes.notError does not implement error"
+
+type badMethods struct {
+	cause error
+}
+
+func (badMethods) Error() string {
+	return "bad"
+}
+
+func (e badMethods) Unwrap() string { // want "method Unwrap of allowed type \\*allowedtypes.badMethods is not used by errors package: want Unwrap\\(\\) error or Unwrap\\(\\) \\[\\]error"
+	return e.cause.Error()
+}
+
+func (badMethods) Is(target error) bool {
+	return false
+}
+
+func (badMethods) As(target error) bool { // want "method As of allowed type"
+	return false
+}
+
+type multi struct {
+	causes []error
+}
+
+func (multi) Error() string {
+	return "multi"
+}
+
+func (e multi) Unwrap() []error {
+	return e.causes
+}
+
+type recursive struct {
+	code int
+}
+
+func (e recursive) Error() string {
+	return fmt.Sprintf("error %v", e) // want "Error method of allowedtypes.recursive formats its receiver by fmt.Sprintf: infinite recursion"
+}
+
+type byCode struct {
+	code int
+}
+
+func (e byCode) Error() string {
+	return fmt.Sprintf("error %d: %v", e.code, e.code)
+}
+
+type ptrRecursive struct{}
+
+func (e *ptrRecursive) Error() string {
+	return fmt.Sprint(e) // want "Error method of \\*allowedtypes.ptrRecursive formats its receiver by fmt.Sprint: infinite recursion"
+}
+
+type goSyntax struct {
+	code int
+}
+
+// %#v prints fields without calling Error
+func (e goSyntax) Error() string {
+	return fmt.Sprintf("error %#v", e)
+}
+
+type hexRecursive struct {
+	code int
+}
+
+func (e hexRecursive) Error() string {
+	return fmt.Sprintf("error %x", e) // want "Error method of allowedtypes.hexRecursive formats its receiver by fmt.Sprintf: infinite recursion"
+}
+
+type upperHexRecursive struct {
+	code int
+}
+
+func (e *upperHexRecursive) Error() string {
+	return fmt.Sprintf("error %X", e) // want "Error method of \\*allowedtypes.upperHexRecursive formats its receiver by fmt.Sprintf: infinite recursion"
+}
@@ -0,0 +1,50 @@
+// package for tests of errors.As targets
+package astarget
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+type myError struct{}
+
+func (e *myError) Error() string {
+	return "my"
+}
+
+func do() error {
+	return &myError{}
+}
+
+func allowedTarget() error {
+	var me *myError
+	if err := do(); errors.As(err, &me) {
+		return me
+	}
+	return nil
+}
+
+func foreignTarget() error {
+	var pe *os.PathError
+	if err := do(); errors.As(err, &pe) {
+		return pe // want `not our type error: \*os.PathError`
+	}
+	return nil
+}
+
+func errorTarget() error {
+	var target error
+	if err := os.Remove("x"); errors.As(err, &target) { // want "error not from our pkg: os"
+		return target
+	}
+	return nil
+}
+
+func ifaceTarget() error {
+	var ne net.Error
+	if err := do(); errors.As(err, &ne) {
+		return ne
+	}
+	return nil
+}
@@ -42,6 +42,26 @@ const (
 	ConstError = myConstError("")
 )
 
+// exported error var for contracts
+var ErrB error = &someError{}
+
 func FunctionFromOtherPkg() error {
 	return &someError{}
 }
+
+// exported type with method returning error for method values
+type Client struct{}
+
+func (*Client) Close() error {
+	return &someError{}
+}
+
+// wrap function of external package
+func Annotate(err error, msg string) error {
+	return &someError{}
+}
+
+// wrap function of external package passing chain through
+func WithStack(err error) error {
+	return &someError{}
+}
@@ -0,0 +1,117 @@
+// package for tests of BoundaryOnly mode
+package boundary
+
+import (
+	"os"
+)
+
+type myError struct{}
+
+func (e *myError) Error() string {
+	return "my"
+}
+
+func open(name string) error {
+	_, err := os.Open(name) // want `error not from our pkg: os \(reaches exported boundary.Open via boundary.open\)` `error not from our pkg: os \(reaches exported \(\*boundary.service\).Get via boundary.open\)`
+	return err
+}
+
+func Open(name string) error { // want Open:`\*os.PathError`
+	return open(name)
+}
+
+// foreign error is handled inside
+func stat(name string) error {
+	if _, err := os.Stat(name); err != nil {
+		return &myError{}
+	}
+	return nil
+}
+
+func Stat(name string) error { // want Stat:`\*boundary.myError`
+	return stat(name)
+}
+
+func remove(name string) error {
+	return os.Remove(name) // want `error not from our pkg: os \(reaches exported boundary.Remove via boundary.cleanup -> boundary.remove\)`
+}
+
+func cleanup(name string) error {
+	return remove(name)
+}
+
+func Remove(name string) error { // want Remove:`\*os.PathError`
+	return cleanup(name)
+}
+
+// never reaches exported functions
+func chdir(name string) error {
+	return os.Chdir(name)
+}
+
+func load() error {
+	return chdir("x")
+}
+
+type service struct{}
+
+func (s *service) Get() error {
+	return open("x")
+}
+
+func Mkdir(name string) error { // want Mkdir:`\*os.PathError`
+	return os.Mkdir(name, 0755) // want "error not from our pkg: os"
+}
+
+// closure returned by exported function
+func Remover(name string) func() error { // want Remover:`\*os.PathError`
+	return func() error {
+		return os.Remove(name) // want `error not from our pkg: os \(in closure returned by boundary.Remover\) \(reaches exported boundary.Remover via boundary.Remover\$1\)`
+	}
+}
+
+type store struct{}
+
+func (s *store) load() error {
+	return os.Chmod("x", 0644) // want `error not from our pkg: os \(reaches exported boundary.Loader via \(\*boundary.store\).load\)`
+}
+
+// method value returned by exported function
+func Loader() func() error { // want Loader:`\*os.PathError`
+	return (&store{}).load
+}
+
+func Invoke() error { // want Invoke:`\*os.PathError`
+	return func() error {
+		_, err := os.Lstat("x") // want `error not from our pkg: os \(reaches exported boundary.Invoke via boundary.Invoke\$1\)`
+		return err
+	}()
+}
+
+type getter interface {
+	get() error
+}
+
+type impl struct{}
+
+// called by interface
+func (impl) get() error {
+	return os.Chown("x", 0, 0) // want `error not from our pkg: os \(may reach exported API: \(boundary.impl\).get is called dynamically\)`
+}
+
+func Get(g getter) error { // want Get:"unknown method boundary.get"
+	return g.get()
+}
+
+func truncate() error {
+	return os.Truncate("x", 0) // want `error not from our pkg: os \(may reach exported API: boundary.truncate is called dynamically\)`
+}
+
+func run(f func() error) error {
+	return f() // want `dynamically dispatched function call: f\(\) \(reaches exported boundary.Truncate via boundary.run\)`
+}
+
+// function value
+func Truncate() error { // want Truncate:"unknown dynamic call parameter f"
+	return run(truncate)
+}
@@ -0,0 +1,92 @@
+// package for tests of cause dropping
+package causes
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type myError struct {
+	msg string
+}
+
+func (e myError) Error() string {
+	return e.msg
+}
+
+type withCause struct {
+	cause error
+}
+
+func (e *withCause) Error() string {
+	return "with cause"
+}
+
+type unwrapped struct {
+	cause error
+}
+
+func (e *unwrapped) Error() string {
+	return e.cause.Error()
+}
+
+func (e *unwrapped) Unwrap() error {
+	return e.cause
+}
+
+func byMessage(err error) error {
+	if err == nil {
+		return nil
+	}
+	return myError{msg: "ctx: " + err.Error()} // want "cause dropped: only message of error parameter err is used"
+}
+
+func noUnwrap(err error) error { // want "cause dropped: error parameter err is stored to field cause of causes.withCause without Unwrap method"
+	return &withCause{cause: err}
+}
+
+func withUnwrap(err error) error {
+	return &unwrapped{cause: err}
+}
+
+func errorfV(err error) error {
+	return fmt.Errorf("ctx: %v", err) // want "cause dropped: error passed to fmt.Errorf with %v, use %w"
+}
+
+func errorfW(err error) error {
+	return fmt.Errorf("ctx %d: %w", 1, err)
+}
+
+func errorfS(err error) error {
+	return fmt.Errorf("%d%% %s", 1, err) // want "cause dropped: error passed to fmt.Errorf with %s, use %w"
+}
+
+// message is only logged
+func logged(err error) error {
+	log.Print(err.Error())
+	return myError{msg: "failed"}
+}
+
+func written(w http.ResponseWriter, err error) error {
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+	return nil
+}
+
+// returned error does not depend on parameter
+func unrelated(err error) error {
+	msg := err.Error()
+	log.Print(msg)
+	return errors.New("unrelated")
+}
+
+type report struct {
+	cause error
+}
+
+// report is not error type
+func toReport(err error, r *report) error {
+	r.cause = err
+	return nil
+}
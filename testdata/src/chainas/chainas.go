@@ -0,0 +1,46 @@
+// package for tests of ChainAs mode
+package chainas
+
+import (
+	"b"
+	"fmt"
+)
+
+type myError struct {
+	cause error
+}
+
+func (e myError) Error() string {
+	return "123"
+}
+
+func (e myError) Unwrap() error {
+	return e.cause
+}
+
+// adds allowed layer
+func wrap(err error) error {
+	return myError{cause: err}
+}
+
+// fmt.Errorf with %w passes chain through without AllowErrorfWrap
+func errorf() error {
+	return fmt.Errorf("ctx: %w", myError{})
+}
+
+func errorfForeign() error {
+	return fmt.Errorf("ctx: %w", b.F()) // want "error not from our pkg: b"
+}
+
+// b.Annotate passes chain through
+func annotate(ok bool) error {
+	if ok {
+		return b.Annotate(myError{}, "ctx")
+	}
+	return b.Annotate(b.F(), "ctx") // want "error not from our pkg: b"
+}
+
+// cause is not checked and its errors are not in error set: only layer type is
+func Get() error { // want Get:"chainas.myError"
+	return wrap(b.F())
+}
@@ -0,0 +1,30 @@
+// package for tests of ChainTop mode
+package chaintop
+
+import (
+	"b"
+	"fmt"
+)
+
+type myError struct {
+	cause error
+}
+
+func (e myError) Error() string {
+	return "123"
+}
+
+// type of error returned by fmt.Errorf is not allowed even with AllowErrorfWrap
+func errorf() error {
+	return fmt.Errorf("ctx: %w", myError{}) // want "error not from our pkg: fmt"
+}
+
+// b.WithStack passes chain through: its type is not allowed
+func withStack() error {
+	return b.WithStack(myError{}) // want "error not from our pkg: b"
+}
+
+// b.Annotate adds allowed layer
+func wrap() error {
+	return b.Annotate(b.F(), "ctx")
+}
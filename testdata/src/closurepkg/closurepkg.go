@@ -0,0 +1,22 @@
+// package for tests of returned closures of package not listed in OurPackages
+package closurepkg
+
+type myError struct{}
+
+func (myError) Error() string {
+	return "123"
+}
+
+func check() error {
+	return myError{}
+}
+
+func checker() func() error {
+	return check
+}
+
+func handler() func() error {
+	return func() error {
+		return myError{}
+	}
+}
@@ -0,0 +1,38 @@
+// package for tests of returned closures
+package closures
+
+import "b"
+
+type myError struct{}
+
+func (myError) Error() string {
+	return "123"
+}
+
+func Handler(ok bool) func() error { // want Handler:`\*b.someError, closures.myError`
+	return func() error {
+		if ok {
+			return myError{}
+		}
+		return b.F() // want "error not from our pkg: b \\(in closure returned by closures.Handler\\)"
+	}
+}
+
+func check() error {
+	return myError{}
+}
+
+// function of our package
+func Checker() func() error { // want Checker:`closures.myError`
+	return check
+}
+
+// method value of external type
+func Closer(c *b.Client) func() error { // want Closer:`\*b.someError`
+	return c.Close // want "error not from our pkg: b \\(returned function value \\(\\*b.Client\\).Close\\)"
+}
+
+// function value of external package
+func Foreign() func() error { // want Foreign:`\*b.someError`
+	return b.F // want "error not from our pkg: b \\(returned function value b.F\\)"
+}
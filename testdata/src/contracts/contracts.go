@@ -0,0 +1,90 @@
+// package for tests of error contracts
+package contracts
+
+import bx "b"
+
+type NotFoundError struct{}
+
+func (*NotFoundError) Error() string {
+	return "not found"
+}
+
+type ConflictError struct{}
+
+func (ConflictError) Error() string {
+	return "conflict"
+}
+
+var ErrClosed error = &NotFoundError{}
+
+// Get declares pointer error by type name
+//myerrorlint:returns NotFoundError
+func Get(ok bool) error { // want Get:`\*contracts.NotFoundError`
+	if ok {
+		return nil
+	}
+	return &NotFoundError{}
+}
+
+//myerrorlint:returns *NotFoundError, ConflictError // want "declared error contracts.ConflictError is never returned"
+func Find() error { // want Find:`\*contracts.NotFoundError`
+	return &NotFoundError{}
+}
+
+//myerrorlint:returns NotFoundError
+func Update(ok bool) error { // want Update:`\*contracts.NotFoundError, contracts.ConflictError` "error contracts.ConflictError is not declared in contract \\(via contracts.update\\)"
+	if ok {
+		return update()
+	}
+	return &NotFoundError{}
+}
+
+func update() error {
+	return ConflictError{}
+}
+
+//myerrorlint:returns ErrClosed, Missing // want "unknown error in contract: Missing"
+func Close() error { // want Close:"contracts.ErrClosed"
+	return ErrClosed // want "cant check error type for global: ErrClosed"
+}
+
+// Repository is storage of items
+type Repository interface {
+	//myerrorlint:returns NotFoundError
+	Get(id int) error // want Get:`\*contracts.NotFoundError`
+	Put(id int) error
+}
+
+type memRepo struct{}
+
+func (memRepo) Get(id int) error { // want "error contracts.ConflictError is not declared in contract of \\(contracts.Repository\\).Get"
+	if id == 0 {
+		return ConflictError{}
+	}
+	return &NotFoundError{}
+}
+
+func (memRepo) Put(id int) error {
+	return ConflictError{}
+}
+
+type dbRepo struct{}
+
+func (*dbRepo) Get(id int) error {
+	return &NotFoundError{}
+}
+
+func (*dbRepo) Put(id int) error {
+	return nil
+}
+
+// caller trusts contract of interface method
+func Load(r Repository) error { // want Load:`\*contracts.NotFoundError`
+	return r.Get(1)
+}
+
+// names of imported packages are resolved by their names in file
+//myerrorlint:returns bx.ErrB
+func Fetch() error { // want Fetch:"b.ErrB"
+	return bx.ErrB // want "cant check error type for global: ErrB"
+}
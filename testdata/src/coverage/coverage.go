@@ -0,0 +1,35 @@
+// package for tests of coverage of error return sites
+package coverage
+
+import "b"
+
+type myError string
+
+func (myError) Error() string {
+	return "123"
+}
+
+func fAllowed() error {
+	return myError("")
+}
+
+func fViolation() error {
+	return b.F() // want "error not from our pkg: b"
+}
+
+func fUnverifiable(err error) error { // want "cant check error type for parameter err"
+	return err
+}
+
+// unsupported case is not reported without ReportUnknown but counted
+func fUnsupported(x interface{}) error {
+	err, _ := x.(error)
+	return err
+}
+
+func fTwoSites(ok bool) error {
+	if ok {
+		return b.F() // want "error not from our pkg: b"
+	}
+	return nil
+}
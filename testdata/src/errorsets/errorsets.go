@@ -0,0 +1,52 @@
+// package for tests of error sets of functions
+package errorsets
+
+import "b"
+
+type myError string
+
+func (myError) Error() string {
+	return "123"
+}
+
+var ErrNotFound error = myError("not found")
+
+func Find() error { // want Find:`\*b.someError`
+	return b.F() // want "error not from our pkg: b"
+}
+
+func helper() error {
+	return ErrNotFound // want "cant check error type for global: ErrNotFound"
+}
+
+func Get(ok bool) error { // want Get:"errorsets.ErrNotFound, errorsets.myError"
+	if ok {
+		return myError("")
+	}
+	return helper()
+}
+
+type repo struct{}
+
+// method of unexported type has no fact
+func (repo) Find() error {
+	return b.F() // want "error not from our pkg: b"
+}
+
+// Even and Odd are mutually recursive, only Even produces error
+func Even(n int) error { // want Even:"errorsets.myError"
+	if n < 0 {
+		return myError("negative")
+	}
+	if n == 0 {
+		return nil
+	}
+	return Odd(n - 1)
+}
+
+func Odd(n int) error { // want Odd:"errorsets.myError"
+	if n == 0 {
+		return nil
+	}
+	return Even(n - 1)
+}
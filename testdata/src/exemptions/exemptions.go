@@ -0,0 +1,29 @@
+// package for tests of interface exemptions
+package exemptions
+
+import "io"
+
+type myError struct{}
+
+func (myError) Error() string {
+	return "123"
+}
+
+type reader struct{}
+
+// errors of io.Reader replace allowed types
+func (reader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, myError{} // want "not our type error: exemptions.myError"
+	}
+	return 0, io.EOF
+}
+
+// not a method of io.Reader
+func (reader) Close() error {
+	return myError{}
+}
+
+func read() error {
+	return io.EOF // want "cant check error type for global: EOF"
+}
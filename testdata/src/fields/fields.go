@@ -0,0 +1,82 @@
+// package for tests of error fields of returned values
+package fields
+
+import "b"
+
+type myError struct{}
+
+func (myError) Error() string {
+	return "123"
+}
+
+type Result struct {
+	ID  int
+	Err error
+}
+
+type BulkResponse struct {
+	Errors []error
+}
+
+func batch(ids []int) []Result {
+	var results []Result
+	for _, id := range ids {
+		if id == 0 {
+			results = append(results, Result{ID: id, Err: b.F()}) // want "error not from our pkg: b"
+			continue
+		}
+		results = append(results, Result{ID: id, Err: myError{}})
+	}
+	return results
+}
+
+func indexed(n int) []Result {
+	results := make([]Result, n)
+	for i := range results {
+		results[i].Err = b.F() // want "error not from our pkg: b"
+	}
+	return results
+}
+
+func bulk() *BulkResponse {
+	errs := []error{myError{}}
+	errs = append(errs, b.F()) // want "error not from our pkg: b"
+	return &BulkResponse{Errors: errs}
+}
+
+func byID() map[int]Result {
+	res := make(map[int]Result)
+	res[1] = Result{Err: b.F()} // want "error not from our pkg: b"
+	return res
+}
+
+// errors in fields are not in error set of function
+func Get() (Result, error) {
+	return Result{Err: b.F()}, nil // want "error not from our pkg: b"
+}
+
+type service struct {
+	last Result
+}
+
+// stored outside of function
+func (s *service) Last() Result {
+	return s.last // want "cant check error type for struct field"
+}
+
+var defaultResult = Result{Err: myError{}}
+
+func byDefault() Result {
+	return defaultResult // want "cant check error type for global: defaultResult"
+}
+
+func first(results []Result) Result {
+	return results[0] // want "cant check error type for slice element"
+}
+
+// local value without stored error
+func empty() Result {
+	var r Result
+	r.ID = 1
+	return r
+}
@@ -0,0 +1,30 @@
+// package for tests of joined errors
+package join
+
+import (
+	"b"
+	"errors"
+)
+
+type myError struct{}
+
+func (myError) Error() string {
+	return "123"
+}
+
+// Combine is our multi-error function
+func Combine(left, right error) error { // want Combine:"unknown parameter" "cant check error type for parameter left" "cant check error type for parameter right"
+	return errors.Join(left, right)
+}
+
+func joinAll() error {
+	return errors.Join(myError{}, b.F()) // want "error not from our pkg: b$"
+}
+
+func joinAllowed() error {
+	return errors.Join(myError{}, myError{})
+}
+
+func combine() error {
+	return Combine(b.F(), myError{}) // want "error not from our pkg: b$"
+}
@@ -0,0 +1,45 @@
+// package for tests of joined errors with JoinAny mode
+package joinany
+
+import (
+	"b"
+	"errors"
+	"fmt"
+)
+
+type myError struct{}
+
+func (myError) Error() string {
+	return "123"
+}
+
+// one allowed joined error is enough
+func joinAny() error {
+	return errors.Join(myError{}, b.F())
+}
+
+func joinNone() error {
+	err := b.F() // want "error not from our pkg: b .joined by errors.Join"
+	return errors.Join(b.F(), err) // want "error not from our pkg: b$"
+}
+
+// same not allowed error joined twice
+func joinSame() error {
+	err := b.F() // want "error not from our pkg: b .joined by errors.Join"
+	return errors.Join(err, err)
+}
+
+func joinWrapped() error {
+	err := b.F() // want "error not from our pkg: b .joined by errors.Join"
+	return errors.Join(err, fmt.Errorf("x: %w", err))
+}
+
+type causer interface {
+	error
+	Cause() error
+}
+
+// unsupported joined error is not allowed even if unknown errors are not reported
+func joinUnknown(c causer) error {
+	return errors.Join(c, b.F()) // want "error not from our pkg: b$"
+}
@@ -0,0 +1,8 @@
+// package of layer errors of b must not escape to
+package api
+
+import "layers/repo"
+
+func Handle() error { // want Handle:`\*b.someError` "error of layer b \\(\\*b.someError\\) must not be returned from layer api \\(via layers/repo.Get -> b.F\\)"
+	return repo.Get()
+}
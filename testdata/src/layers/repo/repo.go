@@ -0,0 +1,8 @@
+// package of layer allowed to return errors of b
+package repo
+
+import "b"
+
+func Get() error { // want Get:`\*b.someError`
+	return b.F()
+}
@@ -0,0 +1,28 @@
+// package for tests of provenance of reported errors
+package provenance
+
+import "b"
+
+type myError string
+
+func (myError) Error() string {
+	return "123"
+}
+
+func fErrorThroughPhi(ok bool) error {
+	var err error
+	if ok {
+		err = myError("")
+	} else {
+		err = b.F() // want `error not from our pkg: b \(.*returned at provenance.go:19\)`
+	}
+	return err
+}
+
+func fErrorThroughFreeVar() (err error) {
+	f := func() error {
+		err = b.F() // want `error not from our pkg: b \(assigned to captured variable err at provenance.go:24 -> returned at provenance.go:25\)`
+		return err
+	}
+	return f()
+}
@@ -0,0 +1,66 @@
+// package for tests of errors made from recovered panics
+package recovered
+
+import (
+	"fmt"
+)
+
+type myError struct {
+	value interface{}
+}
+
+func (e *myError) Error() string {
+	return fmt.Sprint(e.value)
+}
+
+func do() error {
+	return nil
+}
+
+func handleErrorf() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r) // want `error not from our pkg: fmt \(.*returned at recovered.go:26\)`
+		}
+	}()
+	return do()
+}
+
+func handleAssert() (err error) {
+	defer func() {
+		if e, ok := recover().(error); ok { // want "error not from our pkg: recovered panic value"
+			err = e
+		}
+	}()
+	return do()
+}
+
+func handleAllowed() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &myError{value: r}
+		}
+	}()
+	return do()
+}
+
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = &myError{value: r}
+	}
+}
+
+func handleByFunc() (err error) {
+	defer recoverPanic(&err)
+	return do()
+}
+
+// returns only after recovered panic
+func handlePanic() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r) // want `error not from our pkg: fmt \(.*returned after recovered panic at recovered.go:60\)`
+		}
+	}()
+	panic("always")
+}
@@ -0,0 +1,24 @@
+// package for tests of rule config
+package rules
+
+type myError string
+
+func (myError) Error() string {
+	return "123"
+}
+
+var globError error = myError("")
+
+// global rule is disabled
+func fWithGlobError() error {
+	return globError
+}
+
+// parameter rule has warning severity
+func fWithErrorFromParams(err error) error { // want `\[warn\] cant check error type for parameter err : error`
+	return err
+}
+
+func fWithOurError() error {
+	return myError("")
+}
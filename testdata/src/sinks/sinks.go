@@ -0,0 +1,45 @@
+// package for tests of error sinks
+package sinks
+
+import (
+	"b"
+	"encoding/json"
+	"log"
+	"os"
+)
+
+type myError struct{}
+
+func (myError) Error() string {
+	return "123"
+}
+
+func report(code int, msg string) {}
+
+func handle() {
+	err := b.F() // want "error not from our pkg: b .passed to sinks.report" "error not from our pkg: b .passed to log.Printf" "error not from our pkg: b .passed to panic"
+	report(500, err.Error())
+	log.Printf("failed: %v", err)
+	report(400, myError{}.Error())
+	log.Printf("failed: %d %v", 1, myError{})
+	panic(err)
+}
+
+// errors passed to sinks are not added to error set
+func Check() error {
+	report(500, b.F().Error()) // want "error not from our pkg: b"
+	return nil
+}
+
+type errResp struct {
+	Code int
+	Err  error
+}
+
+// error fields of values passed to sinks are checked
+func encode() {
+	err := b.F() // want `error not from our pkg: b \(stored to field Err at sinks.go:\d+ -> passed to \(\*encoding/json.Encoder\).Encode`
+	json.NewEncoder(os.Stdout).Encode(errResp{Code: 500, Err: err})
+	json.NewEncoder(os.Stdout).Encode(&errResp{Err: myError{}})
+	json.NewEncoder(os.Stdout).Encode([]error{myError{}})
+}
@@ -0,0 +1,83 @@
+// package for tests of Unwrap methods
+package unwrap
+
+import "errors"
+
+type myError struct {
+	cause error
+}
+
+func (e *myError) Error() string {
+	return "my"
+}
+
+// may return foreign cause
+func (e *myError) Unwrap() error {
+	return e.cause
+}
+
+type notAllowed struct {
+	cause error
+}
+
+func (e notAllowed) Error() string {
+	return "not allowed"
+}
+
+// Unwrap of not allowed type is checked
+func (e notAllowed) Unwrap() error {
+	return e.cause // want "cant check error type for struct field"
+}
+
+func cause(err *myError) error {
+	return err.Unwrap() // want `error not from our pkg: cause unwrapped by \(\*unwrap.myError\).Unwrap`
+}
+
+func causeOf(err error) error {
+	return errors.Unwrap(err) // want "error not from our pkg: cause unwrapped by errors.Unwrap"
+}
+
+func causeByInterface(err error) error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		return u.Unwrap() // want "error not from our pkg: cause unwrapped by"
+	}
+	return &myError{}
+}
+
+type causer interface {
+	//myerrorlint:returns *myError
+	Unwrap() error
+	Cause() string
+}
+
+// errors of Unwrap are declared by contract
+func causeByContract(c causer) error {
+	return c.Unwrap()
+}
+
+type wrapper interface {
+	Unwrap() error
+	Wrapper()
+}
+
+// errors of Unwrap are set by InterfaceExemptions
+func causeByExemption(w wrapper) error {
+	return w.Unwrap()
+}
+
+type declared struct{}
+
+func (e *declared) Error() string {
+	return "declared"
+}
+
+// Unwrap with contract is checked
+//
+//myerrorlint:returns *myError
+func (e *declared) Unwrap() error {
+	return &myError{}
+}
+
+func causeOfDeclared(e *declared) error {
+	return e.Unwrap()
+}
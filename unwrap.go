@@ -0,0 +1,98 @@
+package myerrorlint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// isUnwrapSignature checks for Unwrap() error or Unwrap() []error
+func isUnwrapSignature(sig *types.Signature) bool {
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	result := sig.Results().At(0).Type()
+	return types.Identical(result, errorType) || types.Identical(result, types.NewSlice(errorType))
+}
+
+// isUnwrapMethod checks if function is Unwrap method of allowed type.
+// It returns cause of error that may be foreign: its returns are not checked, but calls of it are origins of foreign errors
+func isUnwrapMethod(fn *ssa.Function, cfg *Config) bool {
+	recv := fn.Signature.Recv()
+	if recv == nil || fn.Name() != "Unwrap" || !isUnwrapSignature(fn.Signature) {
+		return false
+	}
+	t := recv.Type()
+	if isAllowedErrorType(t, cfg) {
+		return true
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		return isAllowedErrorType(ptr.Elem(), cfg)
+	}
+	return isAllowedErrorType(types.NewPointer(t), cfg)
+}
+
+// isUnwrapCall checks if call returns unwrapped cause: errors.Unwrap, Unwrap method of allowed type or of interface
+func isUnwrapCall(call *ssa.CallCommon, cfg *Config) (bool, string) {
+	if call.IsInvoke() {
+		if call.Method.Name() == "Unwrap" && isUnwrapSignature(call.Method.Type().(*types.Signature)) {
+			return true, call.Method.FullName()
+		}
+		return false, ""
+	}
+	function := call.StaticCallee()
+	if function == nil {
+		return false, ""
+	}
+	if function.Name() == "Unwrap" && funcPkgPath(function) == "errors" && function.Signature.Recv() == nil {
+		return true, function.String()
+	}
+	return isUnwrapMethod(function, cfg), function.String()
+}
+
+// unwrapDeclared checks if errors of Unwrap method are declared by contract or InterfaceExemptions:
+// then it is checked and called like other methods
+func unwrapDeclared(pass *analysis.Pass, res *Result, fn *ssa.Function) bool {
+	if exemptionOf(res, fn) != nil || len(implementedContracts(res, fn)) > 0 {
+		return true
+	}
+	if decl, ok := fn.Syntax().(*ast.FuncDecl); ok {
+		c, _ := parseContract(pass, decl.Doc)
+		return c != nil
+	}
+	return false
+}
+
+// exemptedMethod checks if interface method is method of interface of InterfaceExemptions
+func exemptedMethod(res *Result, method *types.Func) bool {
+	recv := method.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	for _, ex := range res.exemptions {
+		if hasMethod(ex.iface, method.Name()) && types.Implements(recv.Type(), ex.iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUnwrapCall reports unwrapped cause: it is returned by Unwrap method as is and may be foreign
+// Unwrap with declared errors is not checked here
+func (w *walker) checkUnwrapCall(v ssa.CallInstruction, defaultPos token.Pos, p path) bool {
+	ok, name := isUnwrapCall(v.Common(), w.cfg)
+	if !ok {
+		return false
+	}
+	if common := v.Common(); common.IsInvoke() && exemptedMethod(w.res, common.Method) {
+		return false
+	} else if callee := common.StaticCallee(); callee != nil && unwrapDeclared(w.pass, w.res, callee) {
+		return false
+	}
+	w.addSource(ErrorSource{Unknown: "unwrapped by " + name})
+	w.reportf(RuleForeignCall, retPos(v, defaultPos), p, name, "error not from our pkg: cause unwrapped by %s", name)
+	return true
+}
@@ -0,0 +1,62 @@
+package myerrorlint
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// ChainMode values: what is checked for error wrapped by wrap function
+const (
+	// ChainTop - type of returned error must be allowed: only wrap functions adding allowed layer are allowed
+	ChainTop = "top"
+	// ChainAs - errors.As for allowed type must succeed: cause of wrap function passing chain through
+	// (fmt.Errorf with %w, WrapFuncs) is checked
+	ChainAs = "as"
+)
+
+// WrapFunc is a model of wrap function
+type WrapFunc struct {
+	Func      string // qualified name like "github.com/pkg/errors.Wrap"
+	Arg       int    // index of wrapped error argument
+	AddsLayer bool   // returns error of allowed type wrapping cause, otherwise passes chain through
+}
+
+// findWrapFunc returns model of wrap function from WrapFuncs
+func findWrapFunc(function *ssa.Function, cfg *Config) (WrapFunc, bool) {
+	fullName := funcPkgPath(function) + "." + function.Name()
+	for _, wrapFunc := range cfg.WrapFuncs {
+		if wrapFunc.Func == fullName {
+			return wrapFunc, true
+		}
+	}
+	return WrapFunc{}, false
+}
+
+// addsLayer checks if wrap function returns error of allowed type whatever cause is
+func addsLayer(function *ssa.Function, cfg *Config) bool {
+	wrapFunc, ok := findWrapFunc(function, cfg)
+	return ok && wrapFunc.AddsLayer
+}
+
+// checkWrapped checks error wrapped by call of wrap function.
+// Returns false if call should be checked as call of other functions (ChainTop for wrap passing chain through)
+func (w *walker) checkWrapped(function *ssa.Function, wrappedErr ssa.Value, pos token.Pos, p path) bool {
+	p = p.with(pos, "wrapped by "+function.String())
+	if addsLayer(function, w.cfg) {
+		// cause is not checked and its errors are not errors of caller: only layer type is
+		w.addLayerSource(function)
+		w.allowed("WrapFuncs", "%s adds allowed layer", function.String())
+		return true
+	}
+	if w.cfg.ChainMode == ChainTop {
+		// type of returned error is type of wrap function
+		return false
+	}
+	// check that wrapped error is allowed
+	w.addCallSource(function, true)
+	w.wrapped++
+	w.follow(w.current, "wrapped", wrappedErr, pos, p)
+	w.wrapped--
+	return true
+}